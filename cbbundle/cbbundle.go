@@ -0,0 +1,214 @@
+package cbbundle
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/mjschust/lieprod/lie"
+	"github.com/mjschust/lieprod/util"
+)
+
+// A CBFactory constructs conformal blocks bundles of a fixed algebra and level. All bundles
+// built by a factory share a single memoized fusion product.
+type CBFactory interface {
+	Algebra() lie.Algebra
+	Level() int
+	NewBundle(...lie.Weight) CBBundle
+	GenusRank(int, ...lie.Weight) *big.Int
+}
+
+// A CBBundle represents the vector bundle of conformal blocks V(g, ell, λ⃗) on M̄_{0,n}.
+type CBBundle interface {
+	Algebra() lie.Algebra
+	Level() int
+	Weights() []lie.Weight
+	Rank() *big.Int
+	InvariantsDimension() *big.Int
+	InvariantsDifference() *big.Int
+	FirstChernClass() Divisor
+	IntersectFCurve([][]int) *big.Rat
+	SymmetricDivisor() ([]*big.Rat, error)
+}
+
+// NewCBFactory constructs a new factory of conformal blocks bundles of the given algebra and level.
+func NewCBFactory(alg lie.Algebra, ell int) CBFactory {
+	return &cbFactoryImpl{
+		alg:           alg,
+		ell:           ell,
+		fusProd:       alg.FusionProduct(ell),
+		rankDict:      util.NewVectorMap(),
+		degreeDict:    util.NewVectorMap(),
+		genusRankDict: util.NewVectorMap(),
+	}
+}
+
+// NewCBBundle constructs a conformal blocks bundle with its own fusion product.
+func NewCBBundle(alg lie.Algebra, ell int, wts ...lie.Weight) CBBundle {
+	return NewCBFactory(alg, ell).NewBundle(wts...)
+}
+
+type cbFactoryImpl struct {
+	alg           lie.Algebra
+	ell           int
+	fusProd       lie.PolyProduct
+	rankDict      util.VectorMap
+	degreeDict    util.VectorMap
+	genusRankDict util.VectorMap
+	sync.Mutex
+}
+
+func (fact *cbFactoryImpl) Algebra() lie.Algebra {
+	return fact.alg
+}
+
+func (fact *cbFactoryImpl) Level() int {
+	return fact.ell
+}
+
+// NewBundle constructs the bundle with the given weights. The weights are sorted, so the
+// points of M̄_{0,n} are labelled in the order returned by CBBundle.Weights.
+func (fact *cbFactoryImpl) NewBundle(wts ...lie.Weight) CBBundle {
+	return cbBundleImpl{fact, sortWeights(wts)}
+}
+
+// rank computes the rank of the conformal blocks bundle with the given sorted weights, caching the result.
+func (fact *cbFactoryImpl) rank(wts []lie.Weight) *big.Int {
+	key := flatten(wts)
+	fact.Lock()
+	val, present := fact.rankDict.Get(key)
+	fact.Unlock()
+	if present {
+		return val.(*big.Int)
+	}
+
+	rslt := fact.computeRank(wts)
+	fact.Lock()
+	fact.rankDict.Put(key, rslt)
+	fact.Unlock()
+	return rslt
+}
+
+// computeRank computes the rank of the conformal blocks bundle with the given weights without
+// consulting the rank cache. The rank is the multiplicity of the dual of the last weight in the
+// fusion product of the others.
+func (fact *cbFactoryImpl) computeRank(wts []lie.Weight) *big.Int {
+	rslt := big.NewInt(0)
+	if ConformalBlockVanishes(fact.alg, fact.ell, wts...) {
+		return rslt
+	}
+
+	if len(wts) == 0 {
+		rslt.SetInt64(1)
+	} else if len(wts) == 1 {
+		rslt.SetInt64(1)
+	} else {
+		polys := weightPolys(wts[:len(wts)-1])
+		lastWt := fact.alg.Dual(wts[len(wts)-1])
+		rslt.Set(fact.fusProd.Reduce(polys...).Multiplicity(lastWt))
+	}
+
+	return rslt
+}
+
+// factorizationCoeff computes the weighted factorization coefficient of the given partition of
+// weights through the factory's fusion product. See lie.Algebra.WeightedFactorizationCoeff.
+func (fact *cbFactoryImpl) factorizationCoeff(wts1, wts2 []lie.Weight) *big.Rat {
+	if len(wts1) == 0 || len(wts2) == 0 {
+		return big.NewRat(0, 1)
+	}
+	poly1 := fact.fusProd.Reduce(weightPolys(wts1)...)
+	poly2 := fact.fusProd.Reduce(weightPolys(wts2)...)
+
+	rslt := big.NewInt(0)
+	wfSum := big.NewInt(0)
+	for _, mustar := range poly1.Weights() {
+		mu := fact.alg.Dual(mustar)
+		rslt.SetInt64(int64(fact.alg.IntCasimirScalar(mu)))
+		rslt.Mul(rslt, poly1.Multiplicity(mustar))
+		rslt.Mul(rslt, poly2.Multiplicity(mu))
+		wfSum.Add(wfSum, rslt)
+	}
+
+	retVal := new(big.Rat).SetInt(wfSum)
+	return retVal.Quo(retVal, big.NewRat(int64(fact.alg.KillingFactor()), 1))
+}
+
+// weightPolys converts a list of weights to a list of weight polynomials.
+func weightPolys(wts []lie.Weight) []lie.WeightPoly {
+	polys := make([]lie.WeightPoly, len(wts))
+	for i := range wts {
+		polys[i] = wts[i]
+	}
+	return polys
+}
+
+type cbBundleImpl struct {
+	fact *cbFactoryImpl
+	wts  []lie.Weight
+}
+
+func (bdl cbBundleImpl) Algebra() lie.Algebra {
+	return bdl.fact.alg
+}
+
+func (bdl cbBundleImpl) Level() int {
+	return bdl.fact.ell
+}
+
+func (bdl cbBundleImpl) Weights() []lie.Weight {
+	return bdl.wts
+}
+
+// Rank computes the rank of the bundle via factorization through the fusion product.
+func (bdl cbBundleImpl) Rank() *big.Int {
+	return big.NewInt(0).Set(bdl.fact.rank(bdl.wts))
+}
+
+// InvariantsDimension computes the dimension of the space of coinvariants of the tensor product
+// of the representations of the bundle's weights, which contains the conformal block.
+func (bdl cbBundleImpl) InvariantsDimension() *big.Int {
+	return bdl.fact.alg.InvariantsDimension(bdl.wts...)
+}
+
+// InvariantsDifference computes the difference between the dimension of the space of coinvariants
+// and the rank of the bundle. The difference is zero exactly when the conformal block equals the
+// space of coinvariants, which is known to hold above the critical level.
+func (bdl cbBundleImpl) InvariantsDifference() *big.Int {
+	if !ConformalBlockVanishes(bdl.fact.alg, bdl.fact.ell, bdl.wts...) &&
+		bdl.fact.ell > CriticalLevel(bdl.fact.alg, bdl.wts...) {
+		return big.NewInt(0)
+	}
+
+	rslt := bdl.InvariantsDimension()
+	return rslt.Sub(rslt, bdl.Rank())
+}
+
+// sortWeights returns a lexicographically sorted copy of the given weights.
+func sortWeights(wts []lie.Weight) []lie.Weight {
+	rslt := make([]lie.Weight, len(wts))
+	for i := range wts {
+		rslt[i] = make([]int, len(wts[i]))
+		copy(rslt[i], wts[i])
+	}
+	sort.Slice(rslt, func(i, j int) bool { return lessWeight(rslt[i], rslt[j]) })
+	return rslt
+}
+
+func lessWeight(wt1, wt2 lie.Weight) bool {
+	for i := range wt1 {
+		if wt1[i] != wt2[i] {
+			return wt1[i] < wt2[i]
+		}
+	}
+	return false
+}
+
+// flatten concatenates the given weights into a single key.
+func flatten(wts []lie.Weight) []int {
+	key := make([]int, 0, len(wts)*2)
+	for _, wt := range wts {
+		key = append(key, wt...)
+	}
+	return key
+}
@@ -0,0 +1,113 @@
+package cbbundle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+func TestRank(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+		wts       []lie.Weight
+		want      int
+	}{
+		{1, 1, []lie.Weight{}, 1},
+		{1, 1, []lie.Weight{lie.Weight{0}}, 1},
+		{1, 1, []lie.Weight{lie.Weight{1}}, 0},
+		{1, 1, []lie.Weight{lie.Weight{1}, lie.Weight{1}}, 1},
+		{1, 1, []lie.Weight{lie.Weight{1}, lie.Weight{1}, lie.Weight{1}}, 0},
+		{1, 1, []lie.Weight{lie.Weight{1}, lie.Weight{1}, lie.Weight{1}, lie.Weight{1}}, 1},
+		{1, 2, []lie.Weight{lie.Weight{1}, lie.Weight{1}, lie.Weight{1}, lie.Weight{1}}, 2},
+		{1, 2, []lie.Weight{lie.Weight{2}, lie.Weight{1}, lie.Weight{1}, lie.Weight{2}}, 1},
+		{1, 3, []lie.Weight{lie.Weight{1}, lie.Weight{1}, lie.Weight{1}, lie.Weight{1}, lie.Weight{1}, lie.Weight{1}}, 5},
+		{2, 1, []lie.Weight{lie.Weight{1, 0}, lie.Weight{1, 0}, lie.Weight{1, 0}}, 1},
+		{2, 1, []lie.Weight{lie.Weight{1, 0}, lie.Weight{0, 1}}, 1},
+		{2, 1, []lie.Weight{lie.Weight{1, 0}, lie.Weight{1, 0}}, 0},
+		{2, 2, []lie.Weight{lie.Weight{1, 1}, lie.Weight{1, 1}, lie.Weight{1, 1}}, 1},
+		{2, 3, []lie.Weight{lie.Weight{1, 1}, lie.Weight{1, 1}, lie.Weight{1, 1}}, 2},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		bdl := NewCBBundle(alg, c.ell, c.wts...)
+		got := bdl.Rank()
+		if got.Cmp(big.NewInt(int64(c.want))) != 0 {
+			t.Errorf("Rank(%v, %v) = %v, want %v", c.ell, c.wts, got, c.want)
+		}
+	}
+}
+
+func TestSharedFactory(t *testing.T) {
+	alg := lie.NewAlgebra(lie.NewTypeARootSystem(2))
+	fact := NewCBFactory(alg, 2)
+	wts := alg.Weights(2)
+	for _, wt1 := range wts {
+		for _, wt2 := range wts {
+			for _, wt3 := range wts {
+				got := fact.NewBundle(wt1, wt2, wt3).Rank()
+				want := alg.Fusion(2, wt1, wt2).Multiplicity(alg.Dual(wt3))
+				if got.Cmp(want) != 0 {
+					t.Errorf("Rank(%v, %v, %v) = %v, want %v", wt1, wt2, wt3, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestInvariantsDifference(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+		wts       []lie.Weight
+		wantInv   int
+		wantDiff  int
+	}{
+		{1, 1, []lie.Weight{{1}, {1}, {1}, {1}}, 2, 1},
+		{1, 2, []lie.Weight{{1}, {1}, {1}, {1}}, 2, 0},
+		{1, 1, []lie.Weight{{1}, {1}, {1}}, 0, 0},
+		{1, 2, []lie.Weight{{2}, {2}, {2}}, 1, 1},
+		{1, 3, []lie.Weight{{2}, {2}, {2}}, 1, 0},
+		{2, 1, []lie.Weight{{1, 0}, {1, 0}, {1, 0}}, 1, 0},
+		{2, 2, []lie.Weight{{1, 1}, {1, 1}, {1, 1}}, 2, 1},
+		{2, 3, []lie.Weight{{1, 1}, {1, 1}, {1, 1}}, 2, 0},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		bdl := NewCBBundle(alg, c.ell, c.wts...)
+		gotInv := bdl.InvariantsDimension()
+		gotDiff := bdl.InvariantsDifference()
+		if gotInv.Cmp(big.NewInt(int64(c.wantInv))) != 0 || gotDiff.Cmp(big.NewInt(int64(c.wantDiff))) != 0 {
+			t.Errorf("InvariantsDimension(%v, %v), InvariantsDifference(%v, %v) = %v, %v, want %v, %v",
+				c.ell, c.wts, c.ell, c.wts, gotInv, gotDiff, c.wantInv, c.wantDiff)
+		}
+	}
+}
+
+func TestWeightsSorted(t *testing.T) {
+	alg := lie.NewAlgebra(lie.NewTypeARootSystem(2))
+	bdl := NewCBBundle(alg, 2, lie.Weight{1, 1}, lie.Weight{0, 1}, lie.Weight{1, 0}, lie.Weight{0, 1})
+	want := []lie.Weight{{0, 1}, {0, 1}, {1, 0}, {1, 1}}
+	got := bdl.Weights()
+	for i := range want {
+		if !got[i].Equals(want[i]) {
+			t.Errorf("Weights() = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func equals(v1, v2 []int) bool {
+	if len(v1) != len(v2) {
+		return false
+	}
+
+	for i := range v1 {
+		if v1[i] != v2[i] {
+			return false
+		}
+	}
+
+	return true
+}
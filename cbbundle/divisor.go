@@ -0,0 +1,109 @@
+package cbbundle
+
+import (
+	"math/big"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+// A Divisor is a divisor class on M̄_{0,n} expressed in terms of the ψ-classes ψ_1,...,ψ_n and
+// the boundary divisors δ_A.
+type Divisor struct {
+	Psi      []*big.Rat
+	Boundary []BoundaryCoeff
+}
+
+// A BoundaryCoeff is the coefficient of the boundary divisor δ_A. Since δ_A = δ_{A^c}, the
+// subset A is chosen to not contain the last marked point.
+type BoundaryCoeff struct {
+	Subset []int
+	Coeff  *big.Rat
+}
+
+// FirstChernClass computes the first Chern class of the bundle using Fakhruddin's formula,
+// c_1(V) = rank(V) Σ_i c(λ_i) ψ_i - Σ_A b_A δ_A, where c(λ) is the trace anomaly of λ and b_A
+// is the weighted factorization coefficient of the partition A ⊔ A^c divided by 2(ell+h).
+// Marked points are indexed by the position of their weight in Weights().
+func (bdl cbBundleImpl) FirstChernClass() Divisor {
+	alg := bdl.Algebra()
+	ell := bdl.Level()
+	n := len(bdl.wts)
+	if ConformalBlockVanishes(alg, ell, bdl.wts...) {
+		return zeroDivisor(n)
+	}
+
+	// Compute psi coefficients
+	denom := big.NewRat(int64(2*(ell+alg.DualCoxeter())), 1)
+	rank := new(big.Rat).SetInt(bdl.Rank())
+	psi := make([]*big.Rat, n)
+	for i, wt := range bdl.wts {
+		psi[i] = big.NewRat(int64(alg.IntCasimirScalar(wt)), int64(alg.KillingFactor()))
+		psi[i].Mul(psi[i], rank)
+		psi[i].Quo(psi[i], denom)
+	}
+
+	// Compute boundary coefficients
+	boundary := make([]BoundaryCoeff, 0)
+	for _, subset := range boundarySubsets(n) {
+		wts1, wts2 := bdl.partition(subset)
+		coeff := bdl.fact.factorizationCoeff(wts1, wts2)
+		coeff.Neg(coeff.Quo(coeff, denom))
+		boundary = append(boundary, BoundaryCoeff{subset, coeff})
+	}
+
+	return Divisor{psi, boundary}
+}
+
+// zeroDivisor constructs the zero divisor on M̄_{0,n}.
+func zeroDivisor(n int) Divisor {
+	psi := make([]*big.Rat, n)
+	for i := range psi {
+		psi[i] = big.NewRat(0, 1)
+	}
+	boundary := make([]BoundaryCoeff, 0)
+	for _, subset := range boundarySubsets(n) {
+		boundary = append(boundary, BoundaryCoeff{subset, big.NewRat(0, 1)})
+	}
+
+	return Divisor{psi, boundary}
+}
+
+// partition splits the weights of the bundle into those indexed by the given subset and the rest.
+func (bdl cbBundleImpl) partition(subset []int) ([]lie.Weight, []lie.Weight) {
+	inSubset := make([]bool, len(bdl.wts))
+	for _, i := range subset {
+		inSubset[i] = true
+	}
+
+	wts1 := make([]lie.Weight, 0, len(subset))
+	wts2 := make([]lie.Weight, 0, len(bdl.wts)-len(subset))
+	for i, wt := range bdl.wts {
+		if inSubset[i] {
+			wts1 = append(wts1, wt)
+		} else {
+			wts2 = append(wts2, wt)
+		}
+	}
+
+	return wts1, wts2
+}
+
+// boundarySubsets lists the subsets A of {0,...,n-2} with 2 <= |A| <= n-2, which index the
+// boundary divisors of M̄_{0,n}.
+func boundarySubsets(n int) [][]int {
+	retList := make([][]int, 0)
+	var subsetHelper func(subset []int, next int)
+	subsetHelper = func(subset []int, next int) {
+		if len(subset) >= 2 && len(subset) <= n-2 {
+			newSubset := make([]int, len(subset))
+			copy(newSubset, subset)
+			retList = append(retList, newSubset)
+		}
+		for i := next; i < n-1; i++ {
+			subsetHelper(append(subset, i), i+1)
+		}
+	}
+	subsetHelper([]int{}, 0)
+
+	return retList
+}
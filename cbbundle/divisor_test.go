@@ -0,0 +1,111 @@
+package cbbundle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+func TestBoundarySubsets(t *testing.T) {
+	cases := []struct {
+		n    int
+		want [][]int
+	}{
+		{3, [][]int{}},
+		{4, [][]int{{0, 1}, {0, 2}, {1, 2}}},
+		{5, [][]int{{0, 1}, {0, 1, 2}, {0, 1, 3}, {0, 2}, {0, 2, 3}, {0, 3}, {1, 2}, {1, 2, 3},
+			{1, 3}, {2, 3}}},
+	}
+
+	for _, c := range cases {
+		got := boundarySubsets(c.n)
+		if len(got) != len(c.want) {
+			t.Errorf("boundarySubsets(%v) = %v, want %v", c.n, got, c.want)
+			continue
+		}
+		for i := range c.want {
+			if !equals(got[i], c.want[i]) {
+				t.Errorf("boundarySubsets(%v) = %v, want %v", c.n, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestFirstChernClass(t *testing.T) {
+	cases := []struct {
+		rank, ell    int
+		wts          []lie.Weight
+		wantPsi      []*big.Rat
+		wantBoundary []*big.Rat
+	}{
+		{
+			1, 1,
+			[]lie.Weight{{1}, {1}, {1}, {1}},
+			[]*big.Rat{big.NewRat(1, 4), big.NewRat(1, 4), big.NewRat(1, 4), big.NewRat(1, 4)},
+			[]*big.Rat{big.NewRat(0, 1), big.NewRat(0, 1), big.NewRat(0, 1)},
+		},
+		{
+			1, 2,
+			[]lie.Weight{{1}, {1}, {1}, {1}},
+			[]*big.Rat{big.NewRat(3, 8), big.NewRat(3, 8), big.NewRat(3, 8), big.NewRat(3, 8)},
+			[]*big.Rat{big.NewRat(-1, 2), big.NewRat(-1, 2), big.NewRat(-1, 2)},
+		},
+		{
+			1, 1,
+			[]lie.Weight{{1}, {1}, {0}, {0}},
+			[]*big.Rat{big.NewRat(0, 1), big.NewRat(0, 1), big.NewRat(1, 4), big.NewRat(1, 4)},
+			[]*big.Rat{big.NewRat(0, 1), big.NewRat(-1, 4), big.NewRat(-1, 4)},
+		},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		div := NewCBBundle(alg, c.ell, c.wts...).FirstChernClass()
+		for i := range c.wantPsi {
+			if div.Psi[i].Cmp(c.wantPsi[i]) != 0 {
+				t.Errorf("FirstChernClass(%v, %v).Psi = %v, want %v", c.ell, c.wts, div.Psi, c.wantPsi)
+				break
+			}
+		}
+		if len(div.Boundary) != len(c.wantBoundary) {
+			t.Errorf("FirstChernClass(%v, %v) has %v boundary terms, want %v",
+				c.ell, c.wts, len(div.Boundary), len(c.wantBoundary))
+			continue
+		}
+		for i := range c.wantBoundary {
+			if div.Boundary[i].Coeff.Cmp(c.wantBoundary[i]) != 0 {
+				t.Errorf("FirstChernClass(%v, %v).Boundary[%v] = %v, want %v",
+					c.ell, c.wts, div.Boundary[i].Subset, div.Boundary[i].Coeff, c.wantBoundary[i])
+			}
+		}
+	}
+}
+
+func TestFactorizationCoeff(t *testing.T) {
+	alg := lie.NewAlgebra(lie.NewTypeARootSystem(2))
+	fact := NewCBFactory(alg, 2).(*cbFactoryImpl)
+	wts := alg.Weights(2)
+	for _, wt1 := range wts {
+		for _, wt2 := range wts {
+			wts1 := []lie.Weight{wt1, wt2}
+			wts2 := []lie.Weight{wt2, wt1, wt1}
+			got := fact.factorizationCoeff(wts1, wts2)
+			want := alg.WeightedFactorizationCoeff(2, wts1, wts2)
+			if got.Cmp(want) != 0 {
+				t.Errorf("factorizationCoeff(%v, %v) = %v, want %v", wts1, wts2, got, want)
+			}
+		}
+	}
+
+	// Boundary coefficients are computed through the factory's fusion product.
+	bdl := fact.NewBundle(lie.Weight{1, 0}, lie.Weight{1, 0}, lie.Weight{1, 0}, lie.Weight{1, 1}, lie.Weight{1, 1})
+	bdl.Rank()
+	before := fact.fusProd.CacheStats()
+	bdl.FirstChernClass()
+	after := fact.fusProd.CacheStats()
+	if after.Hits+after.Misses == before.Hits+before.Misses {
+		t.Errorf("FirstChernClass did not use the fusion product of the factory")
+	}
+}
@@ -0,0 +1,153 @@
+package cbbundle
+
+import (
+	"math/big"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+// FCurves lists the F-curves of M̄_{0,n}, i.e. the partitions of the marked points {0,...,n-1}
+// into four nonempty parts.
+func FCurves(n int) [][][]int {
+	retList := make([][][]int, 0)
+	blocks := make([]int, n)
+	var partitionHelper func(i, numBlocks int)
+	partitionHelper = func(i, numBlocks int) {
+		if n-i < 4-numBlocks {
+			return
+		}
+		if i == n {
+			partition := make([][]int, 4)
+			for j, block := range blocks {
+				partition[block] = append(partition[block], j)
+			}
+			retList = append(retList, partition)
+			return
+		}
+
+		for block := 0; block < numBlocks; block++ {
+			blocks[i] = block
+			partitionHelper(i+1, numBlocks)
+		}
+		if numBlocks < 4 {
+			blocks[i] = numBlocks
+			partitionHelper(i+1, numBlocks+1)
+		}
+	}
+	partitionHelper(0, 0)
+
+	return retList
+}
+
+// IntersectFCurve computes the intersection degree of the first Chern class of the bundle with the
+// F-curve of the given partition of the marked points into four parts N_1,...,N_4. By factorization,
+// the degree is Σ_μ⃗ deg V(μ_1,...,μ_4) Π_i rank V(λ_{N_i}, μ_i*), where deg V(μ_1,...,μ_4) is the
+// degree of a conformal blocks bundle on M̄_{0,4}. It returns nil if the partition does not consist
+// of four nonempty parts covering each marked point exactly once.
+func (bdl cbBundleImpl) IntersectFCurve(partition [][]int) *big.Rat {
+	if !isFCurve(partition, len(bdl.wts)) {
+		return nil
+	}
+	if bdl.isTrivial() {
+		return big.NewRat(0, 1)
+	}
+
+	prods := make([]lie.WeightPoly, 4)
+	for i, part := range partition {
+		polys := make([]lie.WeightPoly, len(part))
+		for j, pt := range part {
+			polys[j] = bdl.wts[pt]
+		}
+		prods[i] = bdl.fact.fusProd.Reduce(polys...)
+	}
+
+	rslt := big.NewRat(0, 1)
+	coeff := big.NewInt(0)
+	summand := big.NewRat(0, 1)
+	for _, wt1 := range prods[0].Weights() {
+		for _, wt2 := range prods[1].Weights() {
+			for _, wt3 := range prods[2].Weights() {
+				for _, wt4 := range prods[3].Weights() {
+					coeff.Mul(prods[0].Multiplicity(wt1), prods[1].Multiplicity(wt2))
+					coeff.Mul(coeff, prods[2].Multiplicity(wt3))
+					coeff.Mul(coeff, prods[3].Multiplicity(wt4))
+					if coeff.Sign() == 0 {
+						continue
+					}
+
+					summand.SetInt(coeff)
+					summand.Mul(summand, bdl.fact.fourPointDegree(wt1, wt2, wt3, wt4))
+					rslt.Add(rslt, summand)
+				}
+			}
+		}
+	}
+
+	return rslt
+}
+
+// fourPointDegree computes the degree of the conformal blocks bundle on M̄_{0,4} with the given weights.
+func (fact *cbFactoryImpl) fourPointDegree(wt1, wt2, wt3, wt4 lie.Weight) *big.Rat {
+	bdl := fact.NewBundle(wt1, wt2, wt3, wt4).(cbBundleImpl)
+	key := flatten(bdl.wts)
+	fact.Lock()
+	val, present := fact.degreeDict.Get(key)
+	fact.Unlock()
+	if present {
+		return val.(*big.Rat)
+	}
+
+	rank := big.NewInt(0)
+	if !bdl.isTrivial() {
+		rank = fact.rank(bdl.wts)
+	}
+	if rank.Sign() == 0 {
+		rslt := big.NewRat(0, 1)
+		fact.Lock()
+		fact.degreeDict.Put(key, rslt)
+		fact.Unlock()
+		return rslt
+	}
+
+	// Sum the psi and boundary terms; each has degree one on M̄_{0,4}
+	casimirSum := 0
+	for _, wt := range bdl.wts {
+		casimirSum += fact.alg.IntCasimirScalar(wt)
+	}
+	rslt := big.NewRat(int64(casimirSum), int64(fact.alg.KillingFactor()))
+	rslt.Mul(rslt, new(big.Rat).SetInt(rank))
+	for _, subset := range boundarySubsets(4) {
+		wts1, wts2 := bdl.partition(subset)
+		rslt.Sub(rslt, fact.factorizationCoeff(wts1, wts2))
+	}
+
+	rslt.Quo(rslt, big.NewRat(int64(2*(fact.ell+fact.alg.DualCoxeter())), 1))
+	fact.Lock()
+	fact.degreeDict.Put(key, rslt)
+	fact.Unlock()
+	return rslt
+}
+
+// isFCurve returns true if the given partition divides the marked points {0,...,n-1} into four
+// nonempty parts.
+func isFCurve(partition [][]int, n int) bool {
+	if len(partition) != 4 {
+		return false
+	}
+
+	seen := make([]bool, n)
+	numPts := 0
+	for _, part := range partition {
+		if len(part) == 0 {
+			return false
+		}
+		for _, pt := range part {
+			if pt < 0 || pt >= n || seen[pt] {
+				return false
+			}
+			seen[pt] = true
+			numPts++
+		}
+	}
+	return numPts == n
+}
@@ -0,0 +1,155 @@
+package cbbundle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+func TestFCurves(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{3, 0},
+		{4, 1},
+		{5, 10},
+		{6, 65},
+		{7, 350},
+	}
+
+	for _, c := range cases {
+		got := FCurves(c.n)
+		if len(got) != c.want {
+			t.Errorf("len(FCurves(%v)) = %v, want %v", c.n, len(got), c.want)
+		}
+		for _, partition := range got {
+			size := 0
+			for _, part := range partition {
+				if len(part) == 0 {
+					t.Errorf("FCurves(%v) contains %v with an empty part", c.n, partition)
+				}
+				size += len(part)
+			}
+			if size != c.n {
+				t.Errorf("FCurves(%v) contains %v, which is not a partition", c.n, partition)
+			}
+		}
+	}
+}
+
+func TestIntersectFCurve(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+		wts       []lie.Weight
+		partition [][]int
+		want      *big.Rat
+	}{
+		{1, 1, []lie.Weight{{1}, {1}, {1}, {1}}, [][]int{{0}, {1}, {2}, {3}}, big.NewRat(1, 1)},
+		{1, 2, []lie.Weight{{1}, {1}, {1}, {1}}, [][]int{{0}, {1}, {2}, {3}}, big.NewRat(0, 1)},
+		{1, 1, []lie.Weight{{1}, {1}, {1}, {1}, {1}, {1}}, [][]int{{0, 1}, {2}, {3}, {4, 5}}, big.NewRat(0, 1)},
+		{1, 1, []lie.Weight{{1}, {1}, {1}, {1}, {1}, {1}}, [][]int{{0, 1, 2}, {3}, {4}, {5}}, big.NewRat(1, 1)},
+		{2, 1, []lie.Weight{{1, 0}, {1, 0}, {0, 1}, {0, 1}}, [][]int{{0}, {1}, {2}, {3}}, big.NewRat(1, 1)},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		got := NewCBBundle(alg, c.ell, c.wts...).IntersectFCurve(c.partition)
+		if got.Cmp(c.want) != 0 {
+			t.Errorf("IntersectFCurve(%v, %v, %v) = %v, want %v", c.ell, c.wts, c.partition, got, c.want)
+		}
+	}
+}
+
+func TestIntersectInvalidFCurve(t *testing.T) {
+	cases := []struct {
+		ell       int
+		wts       []lie.Weight
+		partition [][]int
+	}{
+		{1, []lie.Weight{{1}, {1}, {1}, {1}}, [][]int{{0, 1}, {2}, {3}}},
+		{1, []lie.Weight{{1}, {1}, {1}, {1}}, [][]int{{0}, {1}, {2}, {3}, {}}},
+		{1, []lie.Weight{{1}, {1}, {1}, {1}, {1}}, [][]int{{0, 1, 4}, {}, {2}, {3}}},
+		{1, []lie.Weight{{1}, {1}, {1}, {1}, {1}}, [][]int{{0}, {1}, {2}, {3}}},
+		{1, []lie.Weight{{1}, {1}, {1}, {1}}, [][]int{{0}, {1}, {2}, {4}}},
+		{1, []lie.Weight{{1}, {1}, {1}, {1}}, [][]int{{0}, {1}, {1}, {3}}},
+		{1, []lie.Weight{{1}, {1}, {1}, {1}}, [][]int{{0}, {1}, {2}, {-1}}},
+		{2, []lie.Weight{{1}, {1}, {1}, {1}}, [][]int{{0}, {1}, {2}}},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(1))
+		if got := NewCBBundle(alg, c.ell, c.wts...).IntersectFCurve(c.partition); got != nil {
+			t.Errorf("IntersectFCurve(%v, %v, %v) = %v, want nil", c.ell, c.wts, c.partition, got)
+		}
+	}
+}
+
+func TestFCurvesAgainstDivisor(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+		wts       []lie.Weight
+	}{
+		{1, 1, []lie.Weight{{1}, {1}, {1}, {1}}},
+		{1, 2, []lie.Weight{{1}, {1}, {2}, {2}}},
+		{1, 2, []lie.Weight{{1}, {1}, {1}, {1}, {2}}},
+		{1, 3, []lie.Weight{{1}, {1}, {1}, {1}, {1}, {1}}},
+		{2, 1, []lie.Weight{{1, 0}, {1, 0}, {1, 0}, {1, 0}, {1, 0}, {0, 1}}},
+		{2, 2, []lie.Weight{{1, 1}, {1, 0}, {0, 1}, {1, 1}, {1, 1}}},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		bdl := NewCBBundle(alg, c.ell, c.wts...)
+		div := bdl.FirstChernClass()
+		for _, partition := range FCurves(len(c.wts)) {
+			got := bdl.IntersectFCurve(partition)
+			want := intersectDivisor(div, partition)
+			if got.Cmp(want) != 0 {
+				t.Errorf("IntersectFCurve(%v, %v, %v) = %v, want %v", c.ell, c.wts, partition, got, want)
+			}
+		}
+	}
+}
+
+// intersectDivisor intersects a divisor with an F-curve using the intersection numbers of
+// ψ-classes and boundary divisors with F-curves.
+func intersectDivisor(div Divisor, partition [][]int) *big.Rat {
+	n := len(div.Psi)
+	rslt := big.NewRat(0, 1)
+	for _, part := range partition {
+		if len(part) == 1 {
+			rslt.Add(rslt, div.Psi[part[0]])
+		}
+	}
+
+	for _, bdryCoeff := range div.Boundary {
+		inSubset := make([]bool, n)
+		for _, i := range bdryCoeff.Subset {
+			inSubset[i] = true
+		}
+		numParts := 0
+		for _, part := range partition {
+			numIn := 0
+			for _, i := range part {
+				if inSubset[i] {
+					numIn++
+				}
+			}
+			if numIn == len(part) {
+				numParts++
+			} else if numIn != 0 {
+				numParts = -1
+				break
+			}
+		}
+		if numParts == 2 {
+			rslt.Add(rslt, bdryCoeff.Coeff)
+		} else if numParts == 1 || numParts == 3 {
+			rslt.Sub(rslt, bdryCoeff.Coeff)
+		}
+	}
+
+	return rslt
+}
@@ -0,0 +1,47 @@
+package cbbundle
+
+import (
+	"math/big"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+// GenusRank computes the rank of the bundle of conformal blocks on M̄_{g,n} with the given weights,
+// i.e. the dimension of the space of generalized theta functions. Ranks in positive genus are computed
+// by factorization at a non-separating node, rank V_g(λ⃗) = Σ_μ rank V_{g-1}(λ⃗, μ, μ*), where μ runs
+// over the integrable weights of the factory's level. The rank is zero for negative genus.
+func (fact *cbFactoryImpl) GenusRank(g int, wts ...lie.Weight) *big.Int {
+	if g < 0 {
+		return big.NewInt(0)
+	}
+	return big.NewInt(0).Set(fact.genusRank(g, sortWeights(wts)))
+}
+
+// genusRank computes the genus g rank with the given sorted weights, caching the result.
+func (fact *cbFactoryImpl) genusRank(g int, wts []lie.Weight) *big.Int {
+	if g == 0 {
+		return fact.rank(wts)
+	}
+
+	key := append([]int{g}, flatten(wts)...)
+	fact.Lock()
+	val, present := fact.genusRankDict.Get(key)
+	fact.Unlock()
+	if present {
+		return val.(*big.Int)
+	}
+
+	rslt := big.NewInt(0)
+	sewnWts := make([]lie.Weight, len(wts)+2)
+	copy(sewnWts, wts)
+	for _, wt := range fact.alg.Weights(fact.ell) {
+		sewnWts[len(wts)] = wt
+		sewnWts[len(wts)+1] = fact.alg.Dual(wt)
+		rslt.Add(rslt, fact.genusRank(g-1, sortWeights(sewnWts)))
+	}
+
+	fact.Lock()
+	fact.genusRankDict.Put(key, rslt)
+	fact.Unlock()
+	return rslt
+}
@@ -0,0 +1,40 @@
+package cbbundle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+func TestGenusRank(t *testing.T) {
+	cases := []struct {
+		rank, ell, g int
+		wts          []lie.Weight
+		want         int
+	}{
+		{1, 1, 0, []lie.Weight{}, 1},
+		{1, 1, 1, []lie.Weight{}, 2},
+		{1, 1, 2, []lie.Weight{}, 4},
+		{1, 1, 3, []lie.Weight{}, 8},
+		{1, 2, 1, []lie.Weight{}, 3},
+		{1, 2, 2, []lie.Weight{}, 10},
+		{1, 2, 3, []lie.Weight{}, 36},
+		{1, 1, 1, []lie.Weight{{1}}, 0},
+		{1, 2, 1, []lie.Weight{{1}, {1}}, 4},
+		{1, 2, 1, []lie.Weight{{2}}, 1},
+		{2, 1, 1, []lie.Weight{}, 3},
+		{2, 1, 2, []lie.Weight{}, 9},
+		{2, 1, 1, []lie.Weight{{1, 0}, {0, 1}}, 3},
+		{1, 1, -1, []lie.Weight{}, 0},
+		{1, 2, -3, []lie.Weight{{1}, {1}}, 0},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		got := NewCBFactory(alg, c.ell).GenusRank(c.g, c.wts...)
+		if got.Cmp(big.NewInt(int64(c.want))) != 0 {
+			t.Errorf("GenusRank(%v, %v, %v) = %v, want %v", c.ell, c.g, c.wts, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cbbundle
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+// SymmetricDivisor computes the first Chern class of a bundle with all weights equal as a divisor on
+// M̄_{0,n}/S_n, in the basis of symmetric boundary divisors B_2,...,B_{⌊n/2⌋}. Using the relation
+// Σ_i ψ_i = Σ_j j(n-j)/(n-1) B_j, the coefficient of B_j is rank(V) c(λ) j(n-j)/(n-1) - b_j, where b_j is the
+// weighted factorization coefficient of a partition into parts of size j and n-j divided by 2(ell+h).
+// The coefficient of B_j is stored at index j-2.
+func (bdl cbBundleImpl) SymmetricDivisor() ([]*big.Rat, error) {
+	n := len(bdl.wts)
+	for _, wt := range bdl.wts {
+		if !wt.Equals(bdl.wts[0]) {
+			return nil, errors.New("cbbundle: weights are not symmetric")
+		}
+	}
+	if n < 4 {
+		return []*big.Rat{}, nil
+	}
+
+	retList := make([]*big.Rat, n/2-1)
+	if bdl.isTrivial() {
+		for i := range retList {
+			retList[i] = big.NewRat(0, 1)
+		}
+		return retList, nil
+	}
+
+	alg := bdl.Algebra()
+	ell := bdl.Level()
+	wt := bdl.wts[0]
+	denom := big.NewRat(int64(2*(ell+alg.DualCoxeter())), 1)
+	weightedRank := big.NewRat(int64(alg.IntCasimirScalar(wt)), int64(alg.KillingFactor()))
+	weightedRank.Mul(weightedRank, new(big.Rat).SetInt(bdl.Rank()))
+	weightedRank.Quo(weightedRank, denom)
+
+	for j := 2; j <= n/2; j++ {
+		coeff := big.NewRat(int64(j*(n-j)), int64(n-1))
+		coeff.Mul(coeff, weightedRank)
+
+		wts1 := make([]lie.Weight, j)
+		wts2 := make([]lie.Weight, n-j)
+		for i := range wts1 {
+			wts1[i] = wt
+		}
+		for i := range wts2 {
+			wts2[i] = wt
+		}
+		bdryCoeff := bdl.fact.factorizationCoeff(wts1, wts2)
+		coeff.Sub(coeff, bdryCoeff.Quo(bdryCoeff, denom))
+		retList[j-2] = coeff
+	}
+
+	return retList, nil
+}
@@ -0,0 +1,101 @@
+package cbbundle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+func TestSymmetricDivisor(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+		wt        lie.Weight
+		n         int
+		want      []*big.Rat
+	}{
+		{1, 1, lie.Weight{1}, 3, []*big.Rat{}},
+		{1, 1, lie.Weight{1}, 4, []*big.Rat{big.NewRat(1, 3)}},
+		{1, 2, lie.Weight{1}, 4, []*big.Rat{big.NewRat(0, 1)}},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		wts := make([]lie.Weight, c.n)
+		for i := range wts {
+			wts[i] = c.wt
+		}
+		got, err := NewCBBundle(alg, c.ell, wts...).SymmetricDivisor()
+		if err != nil {
+			t.Errorf("SymmetricDivisor(%v, %v^%v) returned error %v", c.ell, c.wt, c.n, err)
+			continue
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("SymmetricDivisor(%v, %v^%v) = %v, want %v", c.ell, c.wt, c.n, got, c.want)
+			continue
+		}
+		for i := range c.want {
+			if got[i].Cmp(c.want[i]) != 0 {
+				t.Errorf("SymmetricDivisor(%v, %v^%v) = %v, want %v", c.ell, c.wt, c.n, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSymmetricDivisorNotSymmetric(t *testing.T) {
+	alg := lie.NewAlgebra(lie.NewTypeARootSystem(1))
+	_, err := NewCBBundle(alg, 1, lie.Weight{1}, lie.Weight{1}, lie.Weight{0}, lie.Weight{0}).SymmetricDivisor()
+	if err == nil {
+		t.Errorf("SymmetricDivisor of non-symmetric weights did not return an error")
+	}
+}
+
+func TestSymmetricDivisorAgainstFCurves(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+		wt        lie.Weight
+		n         int
+	}{
+		{1, 1, lie.Weight{1}, 6},
+		{1, 2, lie.Weight{1}, 6},
+		{1, 3, lie.Weight{1}, 7},
+		{2, 1, lie.Weight{1, 0}, 6},
+		{2, 2, lie.Weight{1, 1}, 5},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		wts := make([]lie.Weight, c.n)
+		for i := range wts {
+			wts[i] = c.wt
+		}
+		bdl := NewCBBundle(alg, c.ell, wts...)
+		symDiv, err := bdl.SymmetricDivisor()
+		if err != nil {
+			t.Errorf("SymmetricDivisor(%v, %v^%v) returned error %v", c.ell, c.wt, c.n, err)
+			continue
+		}
+
+		// Expand the symmetric divisor in terms of boundary divisors
+		div := Divisor{make([]*big.Rat, c.n), []BoundaryCoeff{}}
+		for i := range div.Psi {
+			div.Psi[i] = big.NewRat(0, 1)
+		}
+		for _, subset := range boundarySubsets(c.n) {
+			j := len(subset)
+			if j > c.n/2 {
+				j = c.n - j
+			}
+			div.Boundary = append(div.Boundary, BoundaryCoeff{subset, symDiv[j-2]})
+		}
+
+		for _, partition := range FCurves(c.n) {
+			got := intersectDivisor(div, partition)
+			want := bdl.IntersectFCurve(partition)
+			if got.Cmp(want) != 0 {
+				t.Errorf("SymmetricDivisor(%v, %v^%v) . F%v = %v, want %v", c.ell, c.wt, c.n, partition, got, want)
+			}
+		}
+	}
+}
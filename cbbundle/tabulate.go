@@ -0,0 +1,213 @@
+package cbbundle
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+// checkpointInterval is the number of rows written between updates of the checkpoint file.
+var checkpointInterval = 1000
+
+// A checkpointedOutput is an output that can be rolled back to a checkpoint, such as an *os.File.
+type checkpointedOutput interface {
+	io.Writer
+	io.Seeker
+	Truncate(size int64) error
+}
+
+// TabulateRanks enumerates the multisets of n weights of the factory's level, up to the action of
+// the symmetric group and duality, and writes the rank of each conformal blocks bundle as a
+// tab-separated line of weights followed by the rank. All ranks are computed through the
+// factory's fusion product.
+//
+// If checkpoint is not empty, out must support Seek and Truncate, as an *os.File does, and the rows
+// are written at its end. The enumeration position and the length of the output are recorded in
+// the checkpoint file every 1000 rows and when the enumeration is complete. If the checkpoint file
+// exists, the output is truncated to the recorded length, discarding the rows written after the
+// last checkpoint, and the enumeration resumes from the recorded position.
+func TabulateRanks(out io.Writer, fact CBFactory, n int, checkpoint string) error {
+	if n < 0 {
+		return fmt.Errorf("cbbundle: invalid number of weights %v", n)
+	}
+
+	alg, ell := fact.Algebra(), fact.Level()
+	computeRank := func(wts []lie.Weight) *big.Int {
+		return fact.NewBundle(wts...).Rank()
+	}
+	if factImpl, ok := fact.(*cbFactoryImpl); ok {
+		// Avoid caching the rank of every enumerated bundle.
+		computeRank = factImpl.computeRank
+	}
+
+	wts := sortWeights(alg.Weights(ell))
+	dualIndex := make([]int, len(wts))
+	for i, wt := range wts {
+		dualWt := alg.Dual(wt)
+		for j := range wts {
+			if wts[j].Equals(dualWt) {
+				dualIndex[i] = j
+				break
+			}
+		}
+	}
+
+	header := fmt.Sprintf("%v%v %v %v", alg.CartanType(), alg.Rank(), ell, n)
+	start := 0
+	var file checkpointedOutput
+	var buf *bufio.Writer
+	if checkpoint != "" {
+		var ok bool
+		if file, ok = out.(checkpointedOutput); !ok {
+			return errors.New("cbbundle: checkpointed output must support Seek and Truncate")
+		}
+		var err error
+		if start, err = resumeOutput(file, checkpoint, header); err != nil {
+			return err
+		}
+		buf = bufio.NewWriter(file)
+		out = buf
+	}
+	saveCheckpoint := func(pos int) error {
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		offset, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		return writeCheckpoint(checkpoint, header, pos, offset)
+	}
+
+	indices := make([]int, n)
+	dualIndices := make([]int, n)
+	tuple := make([]lie.Weight, n)
+	numRows := 0
+	pos := 0
+	for more := true; more; pos, more = pos+1, nextMultiset(indices, len(wts)) {
+		if pos < start {
+			continue
+		}
+
+		for i, index := range indices {
+			dualIndices[i] = dualIndex[index]
+		}
+		sort.Ints(dualIndices)
+		if lessInts(dualIndices, indices) {
+			continue
+		}
+
+		for i, index := range indices {
+			tuple[i] = wts[index]
+		}
+		if err := writeRow(out, tuple, computeRank(tuple)); err != nil {
+			return err
+		}
+		numRows++
+		if checkpoint != "" && numRows%checkpointInterval == 0 {
+			if err := saveCheckpoint(pos + 1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if checkpoint != "" && pos > start {
+		return saveCheckpoint(pos)
+	}
+	return nil
+}
+
+// nextMultiset advances the given nondecreasing list of indices to the next multiset of
+// {0,...,m-1}, returning false if there is none.
+func nextMultiset(indices []int, m int) bool {
+	i := len(indices) - 1
+	for ; i >= 0; i-- {
+		if indices[i] < m-1 {
+			break
+		}
+	}
+	if i < 0 {
+		return false
+	}
+
+	indices[i]++
+	for j := i + 1; j < len(indices); j++ {
+		indices[j] = indices[i]
+	}
+	return true
+}
+
+func writeRow(out io.Writer, wts []lie.Weight, rank *big.Int) error {
+	fields := make([]string, len(wts)+1)
+	for i, wt := range wts {
+		fields[i] = fmt.Sprint(wt)
+	}
+	fields[len(wts)] = rank.String()
+	_, err := io.WriteString(out, strings.Join(fields, "\t")+"\n")
+	return err
+}
+
+// resumeOutput reads the enumeration position and output length from the checkpoint file, and
+// truncates the output to that length. Without a checkpoint file, the position is zero and the
+// length is the current length of the output. The output is left positioned at its end.
+func resumeOutput(file checkpointedOutput, checkpoint, header string) (int, error) {
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	data, err := ioutil.ReadFile(checkpoint)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var typ string
+	var ell, n, pos int
+	var offset int64
+	if _, err := fmt.Sscan(string(data), &typ, &ell, &n, &pos, &offset); err != nil {
+		return 0, fmt.Errorf("cbbundle: malformed checkpoint %v: %v", checkpoint, err)
+	}
+	if fmt.Sprintf("%v %v %v", typ, ell, n) != header {
+		return 0, fmt.Errorf("cbbundle: checkpoint %v does not match tabulation %v", checkpoint, header)
+	}
+	if offset > size {
+		return 0, fmt.Errorf("cbbundle: output is shorter than recorded in checkpoint %v", checkpoint)
+	}
+
+	if err := file.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return pos, nil
+}
+
+// writeCheckpoint atomically replaces the checkpoint file with the given position and output length.
+func writeCheckpoint(checkpoint, header string, pos int, offset int64) error {
+	tmpFile := checkpoint + ".tmp"
+	data := []byte(fmt.Sprintf("%v %v %v\n", header, pos, offset))
+	if err := ioutil.WriteFile(tmpFile, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, checkpoint)
+}
+
+func lessInts(slc1, slc2 []int) bool {
+	for i := range slc1 {
+		if slc1[i] != slc2[i] {
+			return slc1[i] < slc2[i]
+		}
+	}
+	return false
+}
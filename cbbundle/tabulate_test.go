@@ -0,0 +1,158 @@
+package cbbundle
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+func TestNextMultiset(t *testing.T) {
+	cases := []struct {
+		n, m int
+		want int
+	}{
+		{0, 3, 1},
+		{1, 3, 3},
+		{2, 3, 6},
+		{3, 3, 10},
+		{4, 2, 5},
+	}
+
+	for _, c := range cases {
+		indices := make([]int, c.n)
+		got := 1
+		for nextMultiset(indices, c.m) {
+			got++
+		}
+		if got != c.want {
+			t.Errorf("Number of multisets of size %v of %v elements = %v, want %v", c.n, c.m, got, c.want)
+		}
+	}
+}
+
+func TestTabulateRanks(t *testing.T) {
+	cases := []struct {
+		rank, ell, n int
+		want         []string
+	}{
+		{
+			1, 1, 4,
+			[]string{
+				"[0]\t[0]\t[0]\t[0]\t1",
+				"[0]\t[0]\t[0]\t[1]\t0",
+				"[0]\t[0]\t[1]\t[1]\t1",
+				"[0]\t[1]\t[1]\t[1]\t0",
+				"[1]\t[1]\t[1]\t[1]\t1",
+			},
+		},
+		{
+			2, 1, 2,
+			[]string{
+				"[0 0]\t[0 0]\t1",
+				"[0 0]\t[0 1]\t0",
+				"[0 1]\t[0 1]\t0",
+				"[0 1]\t[1 0]\t1",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		var buf bytes.Buffer
+		err := TabulateRanks(&buf, NewCBFactory(alg, c.ell), c.n, "")
+		if err != nil {
+			t.Errorf("TabulateRanks(%v, %v) returned error %v", c.ell, c.n, err)
+		}
+		got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if strings.Join(got, "\n") != strings.Join(c.want, "\n") {
+			t.Errorf("TabulateRanks(%v, %v) = %q, want %q", c.ell, c.n, got, c.want)
+		}
+	}
+}
+
+// failingFile fails after writing the given number of bytes, writing only part of the data passed
+// to the failing call.
+type failingFile struct {
+	*os.File
+	bytesLeft int
+}
+
+func (f *failingFile) Write(p []byte) (int, error) {
+	if len(p) > f.bytesLeft {
+		n, _ := f.File.Write(p[:f.bytesLeft])
+		f.bytesLeft = 0
+		return n, errors.New("interrupted")
+	}
+	f.bytesLeft -= len(p)
+	return f.File.Write(p)
+}
+
+func TestTabulateRanksResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tabulate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	checkpoint := filepath.Join(dir, "checkpoint")
+	output := filepath.Join(dir, "output")
+	defer func(interval int) { checkpointInterval = interval }(checkpointInterval)
+	checkpointInterval = 3
+
+	alg := lie.NewAlgebra(lie.NewTypeARootSystem(2))
+	fact := NewCBFactory(alg, 2)
+	var want bytes.Buffer
+	if err := TabulateRanks(&want, fact, 3, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Interrupt the tabulation in the middle of a row written after a checkpoint.
+	file, err := os.Create(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interrupted := &failingFile{file, 300}
+	if err := TabulateRanks(interrupted, fact, 3, checkpoint); err == nil {
+		t.Errorf("TabulateRanks did not return the writer error")
+	}
+	file.Close()
+
+	file, err = os.OpenFile(output, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := TabulateRanks(file, fact, 3, checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	got, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want.String() {
+		t.Errorf("Resumed tabulation = %q, want %q", got, want.String())
+	}
+
+	file, err = os.OpenFile(output, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := TabulateRanks(file, fact, 4, checkpoint); err == nil {
+		t.Errorf("TabulateRanks accepted a checkpoint of a different tabulation")
+	}
+	if err := TabulateRanks(file, NewCBFactory(lie.NewAlgebra(lie.NewTypeARootSystem(1)), 2), 3, checkpoint); err == nil {
+		t.Errorf("TabulateRanks accepted a checkpoint of a different algebra")
+	}
+	if err := TabulateRanks(&want, fact, 3, checkpoint); err == nil {
+		t.Errorf("TabulateRanks accepted a checkpointed output without Seek and Truncate")
+	}
+	if err := TabulateRanks(&want, fact, -1, ""); err == nil {
+		t.Errorf("TabulateRanks accepted a negative number of weights")
+	}
+}
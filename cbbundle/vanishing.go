@@ -0,0 +1,87 @@
+package cbbundle
+
+import "github.com/mjschust/lieprod/lie"
+
+// ConformalBlockVanishes returns true if the conformal block of the given algebra and level with the
+// given weights is known to vanish without computing fusion products. It checks that the weights
+// are integrable at level ell, that their sum lies in the root lattice, the cases of at most two
+// weights, and the triangle and level conditions for sl2. A return value of false does not imply the
+// block is nonzero.
+func ConformalBlockVanishes(alg lie.Algebra, ell int, wts ...lie.Weight) bool {
+	for _, wt := range wts {
+		if alg.Level(wt) > ell {
+			return true
+		}
+	}
+
+	// The center acts trivially on coinvariants
+	fundWt := alg.NewWeight()
+	for i := range fundWt {
+		fundWt[i] = 1
+		pairing := 0
+		for _, wt := range wts {
+			pairing += alg.IntKillingForm(wt, fundWt)
+		}
+		fundWt[i] = 0
+		if pairing%alg.KillingFactor() != 0 {
+			return true
+		}
+	}
+
+	switch len(wts) {
+	case 0:
+		return false
+	case 1:
+		return alg.Level(wts[0]) != 0
+	case 2:
+		return !wts[0].Equals(alg.Dual(wts[1]))
+	}
+
+	if alg.Rank() == 1 {
+		sum := 0
+		for _, wt := range wts {
+			sum += wt[0]
+		}
+		for _, wt := range wts {
+			if 2*wt[0] > sum {
+				return true
+			}
+		}
+		if len(wts) == 3 && sum > 2*ell {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CriticalLevel computes the critical level of the given weights of sl(r+1), the minimum of
+// Σ_i |λ_i|/(r+1) - 1 and Σ_i |λ_i*|/(r+1) - 1, where |λ| is the number of boxes in the Young diagram
+// of λ. Above the critical level, the conformal block coincides with the space of coinvariants and
+// its first Chern class vanishes. The sum of the weights is assumed to lie in the root lattice.
+func CriticalLevel(alg lie.Algebra, wts ...lie.Weight) int {
+	lastFundWt := alg.NewWeight()
+	lastFundWt[len(lastFundWt)-1] = 1
+	numBoxes, numDualBoxes := 0, 0
+	for _, wt := range wts {
+		numBoxes += alg.IntKillingForm(wt, lastFundWt)
+		numDualBoxes += alg.IntKillingForm(alg.Dual(wt), lastFundWt)
+	}
+	if numDualBoxes < numBoxes {
+		numBoxes = numDualBoxes
+	}
+
+	return numBoxes/alg.KillingFactor() - 1
+}
+
+// IsCriticalLevel returns true if ell is the critical level of the given weights of sl(r+1).
+func IsCriticalLevel(alg lie.Algebra, ell int, wts ...lie.Weight) bool {
+	return !ConformalBlockVanishes(alg, ell, wts...) && CriticalLevel(alg, wts...) == ell
+}
+
+// isTrivial returns true if the first Chern class of the bundle is known to vanish.
+func (bdl cbBundleImpl) isTrivial() bool {
+	alg := bdl.Algebra()
+	ell := bdl.Level()
+	return ConformalBlockVanishes(alg, ell, bdl.wts...) || ell > CriticalLevel(alg, bdl.wts...)
+}
@@ -0,0 +1,119 @@
+package cbbundle
+
+import (
+	"testing"
+
+	"github.com/mjschust/lieprod/lie"
+)
+
+func TestConformalBlockVanishes(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+		wts       []lie.Weight
+		want      bool
+	}{
+		{1, 1, []lie.Weight{{2}, {2}}, true},
+		{1, 1, []lie.Weight{{1}, {1}, {1}}, true},
+		{1, 2, []lie.Weight{{2}, {1}, {1}}, false},
+		{1, 3, []lie.Weight{{3}, {1}, {1}, {1}}, false},
+		{1, 3, []lie.Weight{{3}, {1}, {1}}, true},
+		{1, 2, []lie.Weight{{2}, {2}, {2}}, true},
+		{1, 1, []lie.Weight{{0}}, false},
+		{1, 1, []lie.Weight{{1}, {1}}, false},
+		{2, 1, []lie.Weight{{1, 0}, {1, 0}}, true},
+		{2, 1, []lie.Weight{{1, 0}, {0, 1}}, false},
+		{2, 1, []lie.Weight{{1, 0}, {1, 0}, {0, 1}}, true},
+		{2, 2, []lie.Weight{{1, 1}, {1, 1}}, false},
+		{2, 2, []lie.Weight{{1, 1}, {2, 0}}, true},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		got := ConformalBlockVanishes(alg, c.ell, c.wts...)
+		if got != c.want {
+			t.Errorf("ConformalBlockVanishes(%v, %v) = %v, want %v", c.ell, c.wts, got, c.want)
+		}
+	}
+}
+
+func TestConformalBlockVanishesAgainstRank(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+	}{
+		{1, 2},
+		{1, 3},
+		{2, 2},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		wts := alg.Weights(c.ell + 1)
+		for _, wt1 := range wts {
+			for _, wt2 := range wts {
+				for _, wt3 := range wts {
+					if !ConformalBlockVanishes(alg, c.ell, wt1, wt2, wt3) {
+						continue
+					}
+					fusion := alg.Fusion(c.ell, wt1, wt2)
+					if alg.Level(wt3) <= c.ell && fusion.Multiplicity(alg.Dual(wt3)).Sign() != 0 {
+						t.Errorf("ConformalBlockVanishes(%v, %v, %v, %v) = true for a nonzero block",
+							c.ell, wt1, wt2, wt3)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCriticalLevel(t *testing.T) {
+	cases := []struct {
+		rank int
+		wts  []lie.Weight
+		want int
+	}{
+		{1, []lie.Weight{{1}, {1}, {1}, {1}}, 1},
+		{1, []lie.Weight{{2}, {2}, {2}, {2}}, 3},
+		{1, []lie.Weight{{3}, {3}, {2}, {2}}, 4},
+		{2, []lie.Weight{{1, 0}, {1, 0}, {1, 0}, {1, 0}, {1, 0}, {1, 0}}, 1},
+		{2, []lie.Weight{{1, 1}, {1, 1}, {1, 1}}, 2},
+		{2, []lie.Weight{{0, 2}, {0, 2}, {0, 2}, {1, 0}, {1, 0}, {1, 0}}, 3},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		got := CriticalLevel(alg, c.wts...)
+		if got != c.want {
+			t.Errorf("CriticalLevel(%v) = %v, want %v", c.wts, got, c.want)
+		}
+		if !IsCriticalLevel(alg, c.want, c.wts...) {
+			t.Errorf("IsCriticalLevel(%v, %v) = false", c.want, c.wts)
+		}
+	}
+}
+
+func TestAboveCriticalLevel(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+		wts       []lie.Weight
+	}{
+		{1, 2, []lie.Weight{{1}, {1}, {1}, {1}}},
+		{1, 3, []lie.Weight{{1}, {1}, {1}, {1}, {2}}},
+		{2, 2, []lie.Weight{{1, 0}, {1, 0}, {1, 0}, {1, 1}}},
+		{2, 4, []lie.Weight{{1, 1}, {1, 1}, {1, 1}, {1, 1}}},
+	}
+
+	for _, c := range cases {
+		alg := lie.NewAlgebra(lie.NewTypeARootSystem(c.rank))
+		bdl := NewCBBundle(alg, c.ell, c.wts...).(cbBundleImpl)
+		if !bdl.isTrivial() {
+			t.Errorf("Bundle(%v, %v) is not known to be trivial", c.ell, c.wts)
+		}
+		div := bdl.FirstChernClass()
+		for _, partition := range FCurves(len(c.wts)) {
+			got := intersectDivisor(div, partition)
+			if got.Sign() != 0 {
+				t.Errorf("FirstChernClass(%v, %v) . F%v = %v, want 0", c.ell, c.wts, partition, got)
+			}
+		}
+	}
+}
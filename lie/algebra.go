@@ -1,6 +1,8 @@
 package lie
 
 import (
+	"context"
+	"fmt"
 	"math/big"
 	"sort"
 
@@ -10,22 +12,109 @@ import (
 // An Algebra supplies representation-theoretic methods acting on Weights.
 type Algebra interface {
 	RootSystem
+	NewWeightPoly() MutableWeightPoly
 	ReprDimension(Weight) *big.Int
+	PolyDimension(WeightPoly) *big.Int
+	DualPoly(WeightPoly) WeightPoly
+	Truncate(int, WeightPoly) WeightPoly
+	DecomposeCharacter(WeightPoly) (WeightPoly, error)
+	Adams(int, WeightPoly) WeightPoly
+	SymmetricPower(Weight, int) WeightPoly
+	ExteriorPower(Weight, int) WeightPoly
+	Plethysm(Weight, []int) WeightPoly
 	DominantChar(Weight) WeightPoly
+	DominantCharContext(context.Context, Weight) (WeightPoly, error)
 	Tensor(...Weight) WeightPoly
+	TensorContext(context.Context, ...Weight) (WeightPoly, error)
+	InvariantsDimension(...Weight) *big.Int
 	TensorProduct() PolyProduct
 	Fusion(int, ...Weight) WeightPoly
+	FusionContext(context.Context, int, ...Weight) (WeightPoly, error)
 	FusionProduct(int) PolyProduct
 	WeightedFactorizationCoeff(int, []Weight, []Weight) *big.Rat
+	SMatrixEntry(int, Weight, Weight) Cyclotomic
+	GaloisAction(int, int, Weight) (Weight, int)
 }
 
 type algebraImpl struct {
 	RootSystem
+	pool        *WorkerPool
+	parallelism int
+	cacheLimit  int64
+	store       *ProductStore
+}
+
+// An AlgebraOption configures an Algebra.
+type AlgebraOption func(*algebraImpl)
+
+// WithProductPool sets the worker pool shared by the products created from the algebra. By default,
+// the pool shared by all products is used.
+func WithProductPool(pool *WorkerPool) AlgebraOption {
+	return func(alg *algebraImpl) {
+		alg.pool = pool
+	}
+}
+
+// WithProductParallelism sets the maximal number of polynomial multiplications run concurrently by
+// the products created from the algebra. See WithParallelism.
+func WithProductParallelism(n int) AlgebraOption {
+	return func(alg *algebraImpl) {
+		if n < 1 {
+			n = 1
+		}
+		alg.parallelism = n
+	}
+}
+
+// WithProductCacheLimit bounds the caches of the memoized products created from the algebra by the
+// given number of monomials. See WithCacheLimit.
+func WithProductCacheLimit(limit int64) AlgebraOption {
+	return func(alg *algebraImpl) {
+		alg.cacheLimit = limit
+	}
+}
+
+// WithProductStore backs the memoized products created from the algebra by the given persistent
+// store, in a namespace determined by the algebra and the product.
+func WithProductStore(store *ProductStore) AlgebraOption {
+	return func(alg *algebraImpl) {
+		alg.store = store
+	}
 }
 
 // NewAlgebra constructs and returns the lie algebra associated to the given root system.
-func NewAlgebra(rtsys RootSystem) Algebra {
-	return algebraImpl{rtsys}
+func NewAlgebra(rtsys RootSystem, opts ...AlgebraOption) Algebra {
+	alg := algebraImpl{RootSystem: rtsys}
+	for _, opt := range opts {
+		opt(&alg)
+	}
+	return alg
+}
+
+// newMemoizedProduct constructs a memoized product using the worker pool, parallelism, cache limit
+// and store of the algebra. The product is stored under the given name, qualified by the algebra.
+func (alg algebraImpl) newMemoizedProduct(name string, prod WeightProduct) PolyProduct {
+	opts := append(alg.productOptions(), WithCacheLimit(alg.cacheLimit))
+	if alg.store != nil {
+		namespace := fmt.Sprintf("%v%v/%v", alg.CartanType(), alg.Rank(), name)
+		opts = append(opts, WithStore(alg.store, namespace))
+	}
+	return NewMemoizedProduct(prod, opts...)
+}
+
+// productOptions returns the options shared by all products created from the algebra.
+func (alg algebraImpl) productOptions() []ProductOption {
+	opts := []ProductOption{WithWorkerPool(alg.pool)}
+	if alg.parallelism > 0 {
+		opts = append(opts, WithParallelism(alg.parallelism))
+	}
+	return opts
+}
+
+// NewWeightPoly constructs a new MutableWeightPoly whose weights are listed in the canonical order
+// of the root system.
+func (alg algebraImpl) NewWeightPoly() MutableWeightPoly {
+	return newPolyBuilder(alg.Rank(), alg.RootSystem)
 }
 
 // ReprDimension returns the dimension of the irreducible representation for the
@@ -53,6 +142,13 @@ func (alg algebraImpl) ReprDimension(highestWt Weight) *big.Int {
 
 // DominantChar builds the dominant character of the representation of the given highest weight.
 func (alg algebraImpl) DominantChar(highestWt Weight) WeightPoly {
+	domChar, _ := alg.DominantCharContext(context.Background(), highestWt)
+	return domChar
+}
+
+// DominantCharContext builds the dominant character of the representation of the given highest
+// weight, returning the error of the context if it is done before the character is complete.
+func (alg algebraImpl) DominantCharContext(ctx context.Context, highestWt Weight) (WeightPoly, error) {
 	// Construct root-level map
 	posRoots := alg.PositiveRoots()
 	rootLevelMap := make(map[int][]Weight)
@@ -77,8 +173,12 @@ func (alg algebraImpl) DominantChar(highestWt Weight) WeightPoly {
 	weightLevelDict := make(map[int]util.VectorMap)
 	weightLevelDict[0] = util.NewVectorMap()
 	weightLevelDict[0].Put(highestWt, true)
-	domChar := NewWeightPolyBuilder(alg.Rank())
+	domChar := alg.NewWeightPoly()
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		done := true
 		for key := range weightLevelDict {
 			if level <= key {
@@ -131,6 +231,9 @@ func (alg algebraImpl) DominantChar(highestWt Weight) WeightPoly {
 	domChar.SetMonomial(highestWt, one)
 	rho := alg.Rho()
 	for _, level := range sortedLevels {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		for _, wt := range weightLevelDict[level].Keys() {
 			var freudenthalHelper func(wt Weight)
 			freudenthalHelper = func(wt Weight) {
@@ -187,22 +290,47 @@ func (alg algebraImpl) DominantChar(highestWt Weight) WeightPoly {
 		}
 	}
 
-	return domChar
+	return domChar.Freeze(), nil
 }
 
 // Tensor computes the tensor product expansion of the given list of weights.
 func (alg algebraImpl) Tensor(wts ...Weight) WeightPoly {
+	rslt, _ := alg.TensorContext(context.Background(), wts...)
+	return rslt
+}
+
+// TensorContext computes the tensor product expansion of the given list of weights, returning the
+// error of the context if it is done before the expansion is complete.
+func (alg algebraImpl) TensorContext(ctx context.Context, wts ...Weight) (WeightPoly, error) {
 	polys := make([]WeightPoly, len(wts))
 	for i := range wts {
 		polys[i] = wts[i]
 	}
-	polyProd := NewProduct(alg.tensorProduct)
-	return polyProd.Reduce(polys...)
+	polyProd := alg.newMemoizedProduct("tensor", alg.tensorProduct)
+	return polyProd.ReduceContext(ctx, polys...)
+}
+
+// InvariantsDimension computes the dimension of the space of invariants of the tensor product of the
+// representations with the given highest weights, i.e. the multiplicity of the dual of the last weight
+// in the tensor product of the others.
+func (alg algebraImpl) InvariantsDimension(wts ...Weight) *big.Int {
+	switch len(wts) {
+	case 0:
+		return big.NewInt(1)
+	case 1:
+		if alg.Level(wts[0]) == 0 {
+			return big.NewInt(1)
+		}
+		return big.NewInt(0)
+	}
+
+	lastWt := alg.Dual(wts[len(wts)-1])
+	return big.NewInt(0).Set(alg.Tensor(wts[:len(wts)-1]...).Multiplicity(lastWt))
 }
 
 // TensorProduct returns a weight polynomial product based on the tensor product
 func (alg algebraImpl) TensorProduct() PolyProduct {
-	return NewProduct(alg.tensorProduct)
+	return alg.newMemoizedProduct("tensor", alg.tensorProduct)
 }
 
 // tensorProduct computes the tensor product decomposition of the given representations.
@@ -221,7 +349,7 @@ func (alg algebraImpl) tensorProduct(wt1, wt2 Weight) MutableWeightPoly {
 	alg.convertWeightToEpc(lamRhoSumWt, lamRhoSum)
 
 	// Construct return map
-	retPoly := NewWeightPolyBuilder(alg.Rank())
+	retPoly := alg.NewWeightPoly()
 	var epc = alg.newEpc()
 	var orbitEpc = alg.newEpc()
 	domWeight := alg.NewWeight()
@@ -254,6 +382,13 @@ func (alg algebraImpl) tensorProduct(wt1, wt2 Weight) MutableWeightPoly {
 
 // Fusion computes the fusion product expansion of the given list of weights.
 func (alg algebraImpl) Fusion(ell int, wts ...Weight) WeightPoly {
+	rslt, _ := alg.FusionContext(context.Background(), ell, wts...)
+	return rslt
+}
+
+// FusionContext computes the fusion product expansion of the given list of weights, returning the
+// error of the context if it is done before the expansion is complete.
+func (alg algebraImpl) FusionContext(ctx context.Context, ell int, wts ...Weight) (WeightPoly, error) {
 	polys := make([]WeightPoly, len(wts))
 	for i := range wts {
 		polys[i] = wts[i]
@@ -261,8 +396,8 @@ func (alg algebraImpl) Fusion(ell int, wts ...Weight) WeightPoly {
 	var prod WeightProduct = func(wt1, wt2 Weight) MutableWeightPoly {
 		return alg.fusionProduct(ell, wt1, wt2)
 	}
-	polyProd := NewMemoizedProduct(prod)
-	return polyProd.Reduce(polys...)
+	polyProd := alg.newMemoizedProduct(fmt.Sprintf("fusion/%v", ell), prod)
+	return polyProd.ReduceContext(ctx, polys...)
 }
 
 // FusionProduct returns a weight polynomial product based on the level ell fusion product
@@ -270,7 +405,7 @@ func (alg algebraImpl) FusionProduct(ell int) PolyProduct {
 	var prod WeightProduct = func(wt1, wt2 Weight) MutableWeightPoly {
 		return alg.fusionProduct(ell, wt1, wt2)
 	}
-	return NewMemoizedProduct(prod)
+	return alg.newMemoizedProduct(fmt.Sprintf("fusion/%v", ell), prod)
 }
 
 // fusionProduct computes the tensor product decomposition of the given representations.
@@ -281,7 +416,7 @@ func (alg algebraImpl) fusionProduct(ell int, wt1, wt2 Weight) MutableWeightPoly
 	tensorDecom := alg.tensorProduct(wt1, wt2)
 
 	// Construct return map
-	retPoly := NewWeightPolyBuilder(alg.Rank())
+	retPoly := alg.NewWeightPoly()
 	domWeight := alg.NewWeight()
 	epc := alg.newEpc()
 	rslt := big.NewInt(0)
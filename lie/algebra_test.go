@@ -1,9 +1,12 @@
 package lie
 
 import (
+	"context"
 	"math/big"
 	"math/rand"
+	"runtime"
 	"testing"
+	"time"
 )
 
 func TestReprDimension(t *testing.T) {
@@ -140,7 +143,7 @@ func TestTensor(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		alg := algebraImpl{c.rtsys}
+		alg := algebraImpl{RootSystem: c.rtsys}
 		tensorDecomp := alg.tensorProduct(c.wt1, c.wt2)
 		if len(tensorDecomp.Weights()) != len(c.wantWts) {
 			t.Errorf("Tensor(%v, %v) contains wrong number of weights", c.wt1, c.wt2)
@@ -207,7 +210,7 @@ func TestFusion(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		alg := algebraImpl{c.rtsys}
+		alg := algebraImpl{RootSystem: c.rtsys}
 		fusionDecomp := alg.fusionProduct(c.ell, c.wt1, c.wt2)
 		// if len(fusionDecomp.Weights()) != len(c.wantWts) {
 		// 	t.Errorf("Fusion(%v, %v, %v) contains wrong number of weights", c.ell, c.wt1, c.wt2)
@@ -319,6 +322,34 @@ func TestMultiTensor(t *testing.T) {
 	}
 }
 
+func TestInvariantsDimension(t *testing.T) {
+	cases := []struct {
+		rtsys RootSystem
+		wts   []Weight
+		want  int
+	}{
+		{typeA{1}, []Weight{}, 1},
+		{typeA{1}, []Weight{Weight{0}}, 1},
+		{typeA{1}, []Weight{Weight{1}}, 0},
+		{typeA{1}, []Weight{Weight{1}, Weight{1}}, 1},
+		{typeA{1}, []Weight{Weight{1}, Weight{1}, Weight{1}}, 0},
+		{typeA{1}, []Weight{Weight{1}, Weight{1}, Weight{1}, Weight{1}}, 2},
+		{typeA{1}, []Weight{Weight{2}, Weight{2}, Weight{2}}, 1},
+		{typeA{2}, []Weight{Weight{1, 0}, Weight{1, 0}}, 0},
+		{typeA{2}, []Weight{Weight{1, 0}, Weight{0, 1}}, 1},
+		{typeA{2}, []Weight{Weight{1, 0}, Weight{1, 0}, Weight{1, 0}}, 1},
+		{typeA{2}, []Weight{Weight{1, 1}, Weight{1, 1}, Weight{1, 1}}, 2},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(c.rtsys)
+		got := alg.InvariantsDimension(c.wts...)
+		if got.Cmp(big.NewInt(int64(c.want))) != 0 {
+			t.Errorf("InvariantsDimension(%v) = %v, want %v", c.wts, got, c.want)
+		}
+	}
+}
+
 func TestWeightedFactorizationCoeff(t *testing.T) {
 	cases := []struct {
 		rtsys      RootSystem
@@ -394,7 +425,7 @@ func TestWeightedFactorizationCoeff(t *testing.T) {
 func BenchmarkTensorSmall(b *testing.B) {
 	rank := 4
 	level := 4
-	alg := algebraImpl{typeA{rank}}
+	alg := algebraImpl{RootSystem: typeA{rank}}
 	wts := alg.Weights(level)
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -409,7 +440,7 @@ func BenchmarkTensorSmall(b *testing.B) {
 func BenchmarkMultiTensorSmall(b *testing.B) {
 	rank := 3
 	level := 4
-	alg := algebraImpl{typeA{rank}}
+	alg := algebraImpl{RootSystem: typeA{rank}}
 	wts := alg.Weights(level)
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -423,7 +454,7 @@ func BenchmarkMultiTensorSmall(b *testing.B) {
 func BenchmarkMultiTensorLarge(b *testing.B) {
 	rank := 5
 	level := 4
-	alg := algebraImpl{typeA{rank}}
+	alg := algebraImpl{RootSystem: typeA{rank}}
 	wts := alg.Weights(level)
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -437,7 +468,7 @@ func BenchmarkMultiTensorLarge(b *testing.B) {
 func BenchmarkTensorLarge(b *testing.B) {
 	rank := 6
 	level := 4
-	alg := algebraImpl{typeA{rank}}
+	alg := algebraImpl{RootSystem: typeA{rank}}
 	wts := alg.Weights(level)
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -451,7 +482,7 @@ func BenchmarkTensorParallel(b *testing.B) {
 	numRoutines := 100
 	rank := 6
 	level := 4
-	alg := algebraImpl{typeA{rank}}
+	alg := algebraImpl{RootSystem: typeA{rank}}
 	wts := alg.Weights(level)
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -468,3 +499,44 @@ func BenchmarkTensorParallel(b *testing.B) {
 		}
 	}
 }
+
+func TestContextCancellation(t *testing.T) {
+	alg := NewAlgebra(NewTypeARootSystem(3))
+	wts := []Weight{{1, 0, 1}, {0, 2, 0}, {1, 1, 0}, {2, 0, 1}}
+	baseline := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got, err := alg.DominantCharContext(ctx, Weight{2, 1, 2}); got != nil || err != context.Canceled {
+		t.Errorf("DominantCharContext with cancelled context = %v, %v, want nil, %v", got, err, context.Canceled)
+	}
+	if got, err := alg.TensorContext(ctx, wts...); got != nil || err != context.Canceled {
+		t.Errorf("TensorContext with cancelled context = %v, %v, want nil, %v", got, err, context.Canceled)
+	}
+	if got, err := alg.FusionContext(ctx, 3, wts...); got != nil || err != context.Canceled {
+		t.Errorf("FusionContext with cancelled context = %v, %v, want nil, %v", got, err, context.Canceled)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	bigWts := []Weight{{2, 1, 2}, {1, 2, 1}, {2, 2, 2}, {3, 0, 3}, {1, 3, 1}, {2, 1, 2}}
+	if got, err := alg.TensorContext(ctx, bigWts...); got != nil || err != context.DeadlineExceeded {
+		t.Errorf("TensorContext with expired deadline = %v, %v, want nil, %v", got, err, context.DeadlineExceeded)
+	}
+
+	// All kernel goroutines are drained before returning.
+	for i := 0; i < 100 && runtime.NumGoroutine() > baseline; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > baseline {
+		t.Errorf("%v goroutines running after cancellation, want at most %v", n, baseline)
+	}
+
+	for _, ell := range []int{2, 4} {
+		want := alg.Fusion(ell, wts...)
+		got, err := alg.FusionContext(context.Background(), ell, wts...)
+		if err != nil || FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+			t.Errorf("FusionContext(%v, %v) = %v, %v, want %v", ell, wts, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package lie
+
+import (
+	"container/list"
+	"math/bits"
+
+	"github.com/mjschust/lieprod/util"
+)
+
+// CacheStats describes the state of the cache of a memoized PolyProduct.
+type CacheStats struct {
+	Entries   int   // number of cached products of weights
+	Cost      int64 // total number of monomials of the cached products, at least one per product
+	Limit     int64 // maximal total cost, or zero if unbounded
+	Hits      int64 // requests served from the cache
+	Misses    int64 // requests not served from the cache
+	Evictions int64 // products evicted to respect the limit
+	Memory    int64 // estimated memory used by the cached products, in bytes
+}
+
+// productCache is a least recently used cache of products of pairs of weights, evicting products
+// once the total number of monomials exceeds the limit. It is not safe for concurrent use.
+type productCache struct {
+	entries util.VectorMap
+	lru     *list.List
+	stats   CacheStats
+}
+
+type cacheEntry struct {
+	key  []int
+	poly WeightPoly
+	cost int64
+	mem  int64
+}
+
+func newProductCache(limit int64) *productCache {
+	return &productCache{util.NewVectorMap(), list.New(), CacheStats{Limit: limit}}
+}
+
+// get returns the cached product for the given key and marks it as recently used.
+func (cache *productCache) get(key []int) (WeightPoly, bool) {
+	val, present := cache.entries.Get(key)
+	if !present {
+		cache.stats.Misses++
+		return nil, false
+	}
+
+	cache.stats.Hits++
+	elt := val.(*list.Element)
+	cache.lru.MoveToFront(elt)
+	return elt.Value.(*cacheEntry).poly, true
+}
+
+// peek returns the cached product for the given key without updating the statistics.
+func (cache *productCache) peek(key []int) (WeightPoly, bool) {
+	val, present := cache.entries.Get(key)
+	if !present {
+		return nil, false
+	}
+	return val.(*list.Element).Value.(*cacheEntry).poly, true
+}
+
+// put caches the product for the given key, evicting the least recently used products if the
+// limit is exceeded. A product whose cost exceeds the limit by itself is not cached.
+func (cache *productCache) put(key []int, poly WeightPoly) {
+	if _, present := cache.entries.Get(key); present {
+		return
+	}
+	entry := &cacheEntry{key, poly, productCost(poly), polyMemory(poly) + int64(len(key))*wordSize}
+	if cache.stats.Limit > 0 && entry.cost > cache.stats.Limit {
+		return
+	}
+
+	cache.entries.Put(key, cache.lru.PushFront(entry))
+	cache.stats.Entries++
+	cache.stats.Cost += entry.cost
+	cache.stats.Memory += entry.mem
+	for cache.stats.Limit > 0 && cache.stats.Cost > cache.stats.Limit {
+		elt := cache.lru.Back()
+		evicted := cache.lru.Remove(elt).(*cacheEntry)
+		cache.entries.Remove(evicted.key)
+		cache.stats.Entries--
+		cache.stats.Cost -= evicted.cost
+		cache.stats.Memory -= evicted.mem
+		cache.stats.Evictions++
+	}
+}
+
+// productCost computes the cost of a cached product as its number of monomials. Empty products cost
+// one, so that they count toward the limit.
+func productCost(poly WeightPoly) int64 {
+	if cost := int64(len(poly.Weights())); cost > 0 {
+		return cost
+	}
+	return 1
+}
+
+// wordSize is the size in bytes of ints, pointers and big.Words.
+const wordSize = bits.UintSize / 8
+
+// polyMemory estimates the memory used by the monomials of a polynomial, counting for each monomial
+// the slice header and labels of its weight and the header and words of its multiplicity.
+func polyMemory(poly WeightPoly) (mem int64) {
+	for _, wt := range poly.Weights() {
+		mem += (3 + int64(len(wt))) * wordSize
+		mem += (4 + int64(len(poly.Multiplicity(wt).Bits()))) * wordSize
+	}
+	return
+}
+
+// pairKey concatenates a pair of weights into a single map key.
+func pairKey(wt1, wt2 Weight) []int {
+	key := make([]int, 0, len(wt1)+len(wt2))
+	return append(append(key, wt1...), wt2...)
+}
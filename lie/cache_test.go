@@ -0,0 +1,113 @@
+package lie
+
+import (
+	"math/big"
+	"testing"
+)
+
+// costPoly constructs a polynomial with the given number of monomials.
+func costPoly(cost int) WeightPoly {
+	poly := NewWeightPolyBuilder(1)
+	for i := 0; i < cost; i++ {
+		poly.SetMonomial(Weight{i}, big.NewInt(1))
+	}
+	return poly.Freeze()
+}
+
+func TestProductCacheEviction(t *testing.T) {
+	cache := newProductCache(5)
+	cache.put([]int{1}, costPoly(2))
+	cache.put([]int{2}, costPoly(2))
+	if _, present := cache.get([]int{1}); !present {
+		t.Errorf("Cache does not contain key [1]")
+	}
+
+	// Key [2] is the least recently used.
+	cache.put([]int{3}, costPoly(2))
+	cases := []struct {
+		key  []int
+		want bool
+	}{
+		{[]int{1}, true},
+		{[]int{2}, false},
+		{[]int{3}, true},
+	}
+	for _, c := range cases {
+		if _, present := cache.peek(c.key); present != c.want {
+			t.Errorf("Cache contains %v = %v, want %v", c.key, present, c.want)
+		}
+	}
+
+	cache.put([]int{4}, costPoly(6))
+	if _, present := cache.peek([]int{4}); present {
+		t.Errorf("Cache contains product exceeding its limit")
+	}
+
+	want := CacheStats{Entries: 2, Cost: 4, Limit: 5, Hits: 1, Misses: 0, Evictions: 1}
+	got := cache.stats
+	if got.Memory <= 0 {
+		t.Errorf("Cache memory estimate = %v, want positive", got.Memory)
+	}
+	got.Memory = 0
+	if got != want {
+		t.Errorf("Cache stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestProductCacheEmptyProducts(t *testing.T) {
+	cache := newProductCache(3)
+	for i := 0; i < 10; i++ {
+		cache.put([]int{i}, costPoly(0))
+	}
+
+	want := CacheStats{Entries: 3, Cost: 3, Limit: 3, Evictions: 7}
+	got := cache.stats
+	got.Memory = 0
+	if got != want {
+		t.Errorf("Cache stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnboundedProductCache(t *testing.T) {
+	cache := newProductCache(0)
+	for i := 0; i < 100; i++ {
+		cache.put([]int{i}, costPoly(i%7))
+	}
+	for i := 0; i < 100; i++ {
+		if _, present := cache.get([]int{i}); !present {
+			t.Errorf("Unbounded cache does not contain key [%v]", i)
+		}
+	}
+	cache.get([]int{100})
+
+	if got := cache.stats; got.Entries != 100 || got.Evictions != 0 || got.Hits != 100 || got.Misses != 1 {
+		t.Errorf("Cache stats = %+v, want 100 entries, 0 evictions, 100 hits and 1 miss", got)
+	}
+}
+
+func TestMemoizedProductCacheLimit(t *testing.T) {
+	alg := NewAlgebra(NewTypeARootSystem(2), WithProductCacheLimit(10))
+	prod := alg.FusionProduct(3)
+	if got := prod.CacheStats().Limit; got != 10 {
+		t.Errorf("CacheStats().Limit = %v, want 10", got)
+	}
+
+	want := NewAlgebra(NewTypeARootSystem(2)).FusionProduct(3)
+	wts := alg.Weights(3)
+	for _, wt1 := range wts {
+		for _, wt2 := range wts {
+			got := prod.Apply(wt1, wt2)
+			if FormatPoly(got, DynkinFmt) != FormatPoly(want.Apply(wt1, wt2), DynkinFmt) {
+				t.Errorf("Apply(%v, %v) = %v, want %v", wt1, wt2, got, want.Apply(wt1, wt2))
+			}
+		}
+	}
+
+	stats := prod.CacheStats()
+	if stats.Cost > 10 || stats.Evictions == 0 || stats.Hits == 0 || stats.Misses == 0 {
+		t.Errorf("CacheStats() = %+v, want cost at most 10 with hits, misses and evictions", stats)
+	}
+	if got := alg.TensorProduct().CacheStats().Limit; got != 10 {
+		t.Errorf("TensorProduct().CacheStats().Limit = %v, want 10", got)
+	}
+}
@@ -0,0 +1,73 @@
+package lie
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// DecomposeCharacter decomposes the given formal character, a polynomial whose weights need not be
+// dominant, into irreducible characters. An error is returned if the polynomial does not have the
+// rank of the algebra or is not invariant under the Weyl group, i.e. is not a virtual character.
+func (alg algebraImpl) DecomposeCharacter(char WeightPoly) (WeightPoly, error) {
+	if char.Rank() != alg.Rank() {
+		return nil, fmt.Errorf("lie: character has rank %v, want %v", char.Rank(), alg.Rank())
+	}
+
+	domChar := alg.NewWeightPoly()
+	wts := char.Weights()
+	for _, wt := range wts {
+		if isDominant(wt) {
+			domChar.SetMonomial(wt, char.Multiplicity(wt))
+		}
+	}
+
+	// Every weight must have the multiplicity of its dominant conjugate, and the orbits of the
+	// dominant weights must account for every weight.
+	domWt := alg.NewWeight()
+	for _, wt := range wts {
+		alg.reflectToChamber(wt, domWt)
+		if char.Multiplicity(wt).Cmp(domChar.Multiplicity(domWt)) != 0 {
+			return nil, fmt.Errorf("lie: multiplicity of %v differs from that of its dominant conjugate %v", wt, domWt)
+		}
+	}
+	numWts := 0
+	epc := alg.newEpc()
+	for _, wt := range domChar.Weights() {
+		alg.convertWeightToEpc(wt, epc)
+		done := false
+		for ; !done; done = alg.nextOrbitEpc(epc) {
+			numWts++
+		}
+	}
+	if numWts != len(wts) {
+		return nil, fmt.Errorf("lie: character is not invariant under the Weyl group")
+	}
+
+	return alg.decomposeDominant(domChar), nil
+}
+
+// decomposeDominant decomposes the W-invariant virtual character with the given dominant weight
+// multiplicities into irreducible characters, by repeatedly subtracting the dominant character
+// of its highest remaining weight. The given polynomial is consumed.
+func (alg algebraImpl) decomposeDominant(domChar MutableWeightPoly) WeightPoly {
+	rslt := alg.NewWeightPoly()
+	term := big.NewInt(0)
+	for {
+		wts := domChar.Weights()
+		if len(wts) == 0 {
+			break
+		}
+
+		highestWt := wts[0]
+		mult := big.NewInt(0).Set(domChar.Multiplicity(highestWt))
+		rslt.AddMonomial(highestWt, mult)
+		char := alg.DominantChar(highestWt)
+		for _, charWt := range char.Weights() {
+			term.Mul(mult, char.Multiplicity(charWt))
+			domChar.AddMonomial(charWt, term.Neg(term))
+		}
+		domChar.Prune()
+	}
+
+	return rslt.Freeze()
+}
@@ -0,0 +1,101 @@
+package lie
+
+import (
+	"math/big"
+	"testing"
+)
+
+// fullChar computes the formal character of the given polynomial of irreducible representations.
+func fullChar(alg algebraImpl, poly WeightPoly) MutableWeightPoly {
+	rslt := alg.NewWeightPoly()
+	epc := alg.newEpc()
+	orbitWt := alg.NewWeight()
+	mult := big.NewInt(0)
+	for _, wt := range poly.Weights() {
+		domChar := alg.DominantChar(wt)
+		for _, domWt := range domChar.Weights() {
+			mult.Mul(poly.Multiplicity(wt), domChar.Multiplicity(domWt))
+			alg.convertWeightToEpc(domWt, epc)
+			done := false
+			for ; !done; done = alg.nextOrbitEpc(epc) {
+				alg.convertEpCoord(epc, orbitWt)
+				rslt.AddMonomial(orbitWt, mult)
+			}
+		}
+	}
+	return rslt
+}
+
+func TestDecomposeCharacter(t *testing.T) {
+	cases := []struct {
+		rtsys RootSystem
+		ms    []monomial
+	}{
+		{typeA{1}, []monomial{}},
+		{typeA{1}, []monomial{{Weight{0}, 1}}},
+		{typeA{1}, []monomial{{Weight{3}, 2}, {Weight{1}, -1}}},
+		{typeA{2}, []monomial{{Weight{1, 1}, 1}}},
+		{typeA{2}, []monomial{{Weight{2, 2}, 1}, {Weight{3, 0}, -2}, {Weight{0, 0}, 5}}},
+		{typeA{3}, []monomial{{Weight{1, 0, 1}, 3}, {Weight{0, 2, 0}, 1}, {Weight{1, 1, 0}, -1}}},
+	}
+
+	for _, c := range cases {
+		alg := algebraImpl{RootSystem: c.rtsys}
+		want := alg.NewWeightPoly()
+		for _, mono := range c.ms {
+			want.SetMonomial(mono.wt, mono.mult())
+		}
+
+		char := fullChar(alg, want)
+		got, err := alg.DecomposeCharacter(char)
+		if err != nil || !want.Equal(got) {
+			t.Errorf("DecomposeCharacter(%v) = %v, %v, want %v", char, got, err, want)
+		}
+	}
+}
+
+func TestDecomposeCharacterErrors(t *testing.T) {
+	cases := []struct {
+		rtsys RootSystem
+		ms    []monomial
+	}{
+		{typeA{1}, []monomial{{Weight{1}, 1}}},
+		{typeA{1}, []monomial{{Weight{-1}, 1}}},
+		{typeA{1}, []monomial{{Weight{1}, 1}, {Weight{-1}, 2}}},
+		{typeA{2}, []monomial{{Weight{1, 0}, 1}, {Weight{-1, 1}, 1}}},
+		{typeA{2}, []monomial{{Weight{1}, 1}}},
+	}
+
+	for _, c := range cases {
+		alg := algebraImpl{RootSystem: c.rtsys}
+		char := NewWeightPolyBuilder(len(c.ms[0].wt))
+		for _, mono := range c.ms {
+			char.SetMonomial(mono.wt, mono.mult())
+		}
+
+		if got, err := alg.DecomposeCharacter(char); err == nil {
+			t.Errorf("DecomposeCharacter(%v) = %v, want error", char, got)
+		}
+	}
+}
+
+func TestDecomposeTensorCharacter(t *testing.T) {
+	alg := algebraImpl{RootSystem: typeA{2}}
+	char1 := fullChar(alg, Weight{1, 1})
+	char2 := fullChar(alg, Weight{2, 0})
+	char := alg.NewWeightPoly()
+	wt := alg.NewWeight()
+	mult := big.NewInt(0)
+	for _, wt1 := range char1.Weights() {
+		for _, wt2 := range char2.Weights() {
+			wt.AddWeights(wt1, wt2)
+			char.AddMonomial(wt, mult.Mul(char1.Multiplicity(wt1), char2.Multiplicity(wt2)))
+		}
+	}
+
+	got, err := alg.DecomposeCharacter(char)
+	if want := alg.Tensor(Weight{1, 1}, Weight{2, 0}); err != nil || !char.Equal(fullChar(alg, got)) ||
+		FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+		t.Errorf("DecomposeCharacter(char [1,1] * char [2,0]) = %v, %v, want %v", got, err, want)
+	}
+}
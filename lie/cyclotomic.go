@@ -0,0 +1,214 @@
+package lie
+
+import (
+	"math"
+	"math/big"
+	"math/cmplx"
+)
+
+// Cyclotomic represents an element of the cyclotomic integers Z[ζ], where ζ is a
+// primitive root of unity of the given order.
+type Cyclotomic struct {
+	order  int
+	coeffs []*big.Int
+}
+
+// NewCyclotomic constructs the zero element of Z[ζ] for a root of unity of the given order.
+func NewCyclotomic(order int) Cyclotomic {
+	coeffs := make([]*big.Int, order)
+	for i := range coeffs {
+		coeffs[i] = big.NewInt(0)
+	}
+	return Cyclotomic{order, coeffs}
+}
+
+// RootOfUnity constructs ζ^power for a root of unity ζ of the given order.
+func RootOfUnity(order, power int) Cyclotomic {
+	rslt := NewCyclotomic(order)
+	rslt.coeffs[mod(power, order)].SetInt64(1)
+	return rslt
+}
+
+// Order returns the order of the root of unity generating the ring.
+func (x Cyclotomic) Order() int {
+	return x.order
+}
+
+// Coeff returns the coefficient of ζ^power in the (unreduced) representation of the element.
+func (x Cyclotomic) Coeff(power int) *big.Int {
+	return big.NewInt(0).Set(x.coeffs[mod(power, x.order)])
+}
+
+// Add returns the sum of the given elements.
+func (x Cyclotomic) Add(y Cyclotomic) Cyclotomic {
+	x, y = liftCommon(x, y)
+	rslt := NewCyclotomic(x.order)
+	for i := range rslt.coeffs {
+		rslt.coeffs[i].Add(x.coeffs[i], y.coeffs[i])
+	}
+	return rslt
+}
+
+// Sub returns the difference of the given elements.
+func (x Cyclotomic) Sub(y Cyclotomic) Cyclotomic {
+	x, y = liftCommon(x, y)
+	rslt := NewCyclotomic(x.order)
+	for i := range rslt.coeffs {
+		rslt.coeffs[i].Sub(x.coeffs[i], y.coeffs[i])
+	}
+	return rslt
+}
+
+// Mul returns the product of the given elements.
+func (x Cyclotomic) Mul(y Cyclotomic) Cyclotomic {
+	x, y = liftCommon(x, y)
+	rslt := NewCyclotomic(x.order)
+	term := big.NewInt(0)
+	for i, a := range x.coeffs {
+		if a.Sign() == 0 {
+			continue
+		}
+		for j, b := range y.coeffs {
+			if b.Sign() == 0 {
+				continue
+			}
+			k := (i + j) % x.order
+			rslt.coeffs[k].Add(rslt.coeffs[k], term.Mul(a, b))
+		}
+	}
+	return rslt
+}
+
+// Conj returns the complex conjugate of the element.
+func (x Cyclotomic) Conj() Cyclotomic {
+	return x.Galois(-1)
+}
+
+// Galois applies the Galois automorphism ζ -> ζ^a to the element. The automorphism
+// is only well-defined when a is coprime to the order.
+func (x Cyclotomic) Galois(a int) Cyclotomic {
+	rslt := NewCyclotomic(x.order)
+	for i, c := range x.coeffs {
+		k := mod(a*i, x.order)
+		rslt.coeffs[k].Add(rslt.coeffs[k], c)
+	}
+	return rslt
+}
+
+// IsZero returns true if the element is zero.
+func (x Cyclotomic) IsZero() bool {
+	rem := reduceCyclotomic(x.coeffs, cyclotomicPoly(x.order))
+	for _, c := range rem {
+		if c.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equals returns true if the elements are equal.
+func (x Cyclotomic) Equals(y Cyclotomic) bool {
+	return x.Sub(y).IsZero()
+}
+
+// Complex returns a floating point approximation of the element.
+func (x Cyclotomic) Complex() complex128 {
+	var rslt complex128
+	for i, c := range x.coeffs {
+		if c.Sign() == 0 {
+			continue
+		}
+		f, _ := new(big.Float).SetInt(c).Float64()
+		rslt += complex(f, 0) * cmplx.Rect(1, 2*math.Pi*float64(i)/float64(x.order))
+	}
+	return rslt
+}
+
+// liftCommon rewrites the given elements over a common cyclotomic ring.
+func liftCommon(x, y Cyclotomic) (Cyclotomic, Cyclotomic) {
+	if x.order == y.order {
+		return x, y
+	}
+	order := x.order / gcd(x.order, y.order) * y.order
+	return x.lift(order), y.lift(order)
+}
+
+func (x Cyclotomic) lift(order int) Cyclotomic {
+	rslt := NewCyclotomic(order)
+	step := order / x.order
+	for i, c := range x.coeffs {
+		rslt.coeffs[i*step].Set(c)
+	}
+	return rslt
+}
+
+// cyclotomicPoly computes the coefficients of the n-th cyclotomic polynomial, lowest degree first.
+func cyclotomicPoly(n int) []int {
+	// Start with x^n - 1 and divide out the cyclotomic polynomials of the proper divisors.
+	rslt := make([]int, n+1)
+	rslt[0] = -1
+	rslt[n] = 1
+	for d := 1; d < n; d++ {
+		if n%d != 0 {
+			continue
+		}
+		divisor := cyclotomicPoly(d)
+		quot := make([]int, len(rslt)-len(divisor)+1)
+		for i := len(quot) - 1; i >= 0; i-- {
+			c := rslt[i+len(divisor)-1]
+			quot[i] = c
+			for j := range divisor {
+				rslt[i+j] -= c * divisor[j]
+			}
+		}
+		rslt = quot
+	}
+	return rslt
+}
+
+// reduceCyclotomic computes the remainder of the given polynomial modulo the monic polynomial phi.
+func reduceCyclotomic(coeffs []*big.Int, phi []int) []*big.Int {
+	rem := make([]*big.Int, len(coeffs))
+	for i := range coeffs {
+		rem[i] = big.NewInt(0).Set(coeffs[i])
+	}
+
+	deg := len(phi) - 1
+	term := big.NewInt(0)
+	for i := len(rem) - 1; i >= deg; i-- {
+		c := big.NewInt(0).Set(rem[i])
+		if c.Sign() == 0 {
+			continue
+		}
+		for j := range phi {
+			term.SetInt64(int64(phi[j]))
+			rem[i-deg+j].Sub(rem[i-deg+j], term.Mul(term, c))
+		}
+	}
+
+	if len(rem) > deg {
+		return rem[:deg]
+	}
+	return rem
+}
+
+func mod(a, n int) int {
+	a %= n
+	if a < 0 {
+		a += n
+	}
+	return a
+}
+
+func gcd(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
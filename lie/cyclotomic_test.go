@@ -0,0 +1,89 @@
+package lie
+
+import (
+	"math/big"
+	"math/cmplx"
+	"testing"
+)
+
+func TestCyclotomicPoly(t *testing.T) {
+	cases := []struct {
+		n    int
+		want []int
+	}{
+		{1, []int{-1, 1}},
+		{2, []int{1, 1}},
+		{3, []int{1, 1, 1}},
+		{4, []int{1, 0, 1}},
+		{6, []int{1, -1, 1}},
+		{8, []int{1, 0, 0, 0, 1}},
+		{12, []int{1, 0, -1, 0, 1}},
+	}
+
+	for _, c := range cases {
+		got := cyclotomicPoly(c.n)
+		if !equals(got, c.want) {
+			t.Errorf("cyclotomicPoly(%v) = %v, want %v", c.n, got, c.want)
+		}
+	}
+}
+
+func TestCyclotomicIsZero(t *testing.T) {
+	cases := []struct {
+		order  int
+		coeffs []int
+		want   bool
+	}{
+		{1, []int{0}, true},
+		{1, []int{1}, false},
+		{3, []int{1, 1, 1}, true},
+		{3, []int{1, 1, 0}, false},
+		{4, []int{1, 0, 1, 0}, true},
+		{4, []int{0, 1, 0, 1}, true},
+		{6, []int{1, 0, 0, 1, 0, 0}, true},
+		{6, []int{0, 1, 0, 0, 0, 1}, false},
+		{12, []int{1, 0, 0, 0, -1, 0, 0, 0, 1, 0, 0, 0}, false},
+	}
+
+	for _, c := range cases {
+		x := NewCyclotomic(c.order)
+		for i, coeff := range c.coeffs {
+			x.coeffs[i].SetInt64(int64(coeff))
+		}
+		got := x.IsZero()
+		if got != c.want {
+			t.Errorf("Cyclotomic(%v, %v).IsZero() = %v, want %v", c.order, c.coeffs, got, c.want)
+		}
+	}
+}
+
+func TestCyclotomicArithmetic(t *testing.T) {
+	cases := []struct {
+		got, want Cyclotomic
+	}{
+		{RootOfUnity(2, 1), RootOfUnity(4, 2)},
+		{RootOfUnity(3, 1).Mul(RootOfUnity(3, 2)), RootOfUnity(1, 0)},
+		{RootOfUnity(4, 1).Mul(RootOfUnity(4, 1)), RootOfUnity(2, 1)},
+		{RootOfUnity(6, 1).Add(RootOfUnity(6, 5)), RootOfUnity(6, 0)},
+		{RootOfUnity(8, 1).Conj(), RootOfUnity(8, 7)},
+		{RootOfUnity(8, 1).Galois(3), RootOfUnity(8, 3)},
+		{RootOfUnity(5, 2).Sub(RootOfUnity(5, 2)), NewCyclotomic(5)},
+		{RootOfUnity(3, 1).Add(RootOfUnity(4, 1)), RootOfUnity(12, 4).Add(RootOfUnity(12, 3))},
+	}
+
+	for _, c := range cases {
+		if !c.got.Equals(c.want) {
+			t.Errorf("Cyclotomic %v, want %v", c.got.Complex(), c.want.Complex())
+		}
+	}
+}
+
+func TestCyclotomicComplex(t *testing.T) {
+	x := RootOfUnity(4, 1).Mul(RootOfUnity(4, 0).Add(RootOfUnity(4, 0)))
+	if cmplx.Abs(x.Complex()-2i) > 1e-9 {
+		t.Errorf("Complex() = %v, want %v", x.Complex(), 2i)
+	}
+	if x.Coeff(5).Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("Coeff(5) = %v, want %v", x.Coeff(5), 2)
+	}
+}
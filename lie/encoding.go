@@ -0,0 +1,299 @@
+package lie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/mjschust/lieprod/util"
+)
+
+// polyEncodingVersion is the leading byte of the binary encoding of a weight polynomial.
+const polyEncodingVersion = 1
+
+// polyEnvelope is the JSON representation of a weight polynomial. The type is omitted for
+// polynomials that are not attached to a root system.
+type polyEnvelope struct {
+	Type  string     `json:"type,omitempty"`
+	Rank  int        `json:"rank"`
+	Terms []polyTerm `json:"terms"`
+}
+
+// polyTerm is the JSON representation of a monomial, with the multiplicity as a decimal string.
+type polyTerm struct {
+	Weight Weight `json:"weight"`
+	Mult   string `json:"mult"`
+}
+
+// MarshalJSON encodes the weight as a list of Dynkin labels.
+func (wt Weight) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int(wt))
+}
+
+// UnmarshalJSON decodes a list of Dynkin labels into the weight.
+func (wt *Weight) UnmarshalJSON(data []byte) error {
+	var labels []int
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return err
+	}
+	*wt = labels
+	return nil
+}
+
+// MarshalBinary encodes the weight as its length followed by its varint Dynkin labels.
+func (wt Weight) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, binary.MaxVarintLen64*(len(wt)+1))
+	buf = appendUvarint(buf, uint64(len(wt)))
+	for _, coord := range wt {
+		buf = appendVarint(buf, int64(coord))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a weight encoded by MarshalBinary.
+func (wt *Weight) UnmarshalBinary(data []byte) error {
+	rdr := bytes.NewReader(data)
+	rank, err := binary.ReadUvarint(rdr)
+	if err != nil {
+		return err
+	}
+	rslt, err := readWeight(rdr, rank)
+	if err != nil {
+		return err
+	}
+	if rdr.Len() != 0 {
+		return errors.New("lie: trailing data after weight")
+	}
+	*wt = rslt
+	return nil
+}
+
+// MarshalJSON encodes the polynomial together with its algebra type and rank. Monomials are listed
+// in canonical order and multiplicities are encoded as decimal strings.
+func (poly hashPolyBuilder) MarshalJSON() ([]byte, error) {
+	return marshalPolyJSON(poly, poly.rtsys)
+}
+
+// MarshalJSON encodes the polynomial in the same format as a MutableWeightPoly.
+func (poly *frozenPoly) MarshalJSON() ([]byte, error) {
+	return marshalPolyJSON(poly, poly.rtsys)
+}
+
+func marshalPolyJSON(poly WeightPoly, rtsys RootSystem) ([]byte, error) {
+	env := polyEnvelope{Rank: poly.Rank(), Terms: []polyTerm{}}
+	if rtsys != nil {
+		env.Type = rtsys.CartanType()
+	}
+	for _, wt := range poly.Weights() {
+		env.Terms = append(env.Terms, polyTerm{wt, poly.Multiplicity(wt).String()})
+	}
+	return json.Marshal(env)
+}
+
+// UnmarshalJSON replaces the contents of the polynomial with the decoded one. If the polynomial is
+// attached to a root system, the encoded algebra type and rank must match it; otherwise the
+// polynomial adopts the encoded ones.
+func (poly *hashPolyBuilder) UnmarshalJSON(data []byte) error {
+	var env polyEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+
+	rtsys, err := poly.decodeHeader(env.Type, env.Rank)
+	if err != nil {
+		return err
+	}
+	vmap := util.NewVectorMap()
+	for _, term := range env.Terms {
+		if len(term.Weight) != env.Rank {
+			return fmt.Errorf("lie: weight %v does not have rank %v", term.Weight, env.Rank)
+		}
+		mult, ok := big.NewInt(0).SetString(term.Mult, 10)
+		if !ok {
+			return fmt.Errorf("lie: invalid multiplicity %q", term.Mult)
+		}
+		addEncodedMonomial(vmap, term.Weight, mult)
+	}
+
+	poly.rank, poly.vmap, poly.rtsys, poly.order = env.Rank, vmap, rtsys, &keyOrder{}
+	return nil
+}
+
+// MarshalBinary encodes the polynomial as a version byte, the algebra type and rank, the number of
+// monomials, and for each monomial its varint Dynkin labels followed by its length-prefixed
+// gob-encoded multiplicity.
+func (poly hashPolyBuilder) MarshalBinary() ([]byte, error) {
+	return marshalPolyBinary(poly, poly.rtsys)
+}
+
+// MarshalBinary encodes the polynomial in the same format as a MutableWeightPoly.
+func (poly *frozenPoly) MarshalBinary() ([]byte, error) {
+	return marshalPolyBinary(poly, poly.rtsys)
+}
+
+func marshalPolyBinary(poly WeightPoly, rtsys RootSystem) ([]byte, error) {
+	typ := ""
+	if rtsys != nil {
+		typ = rtsys.CartanType()
+	}
+	wts := poly.Weights()
+
+	buf := []byte{polyEncodingVersion}
+	buf = appendUvarint(buf, uint64(len(typ)))
+	buf = append(buf, typ...)
+	buf = appendUvarint(buf, uint64(poly.Rank()))
+	buf = appendUvarint(buf, uint64(len(wts)))
+	for _, wt := range wts {
+		for _, coord := range wt {
+			buf = appendVarint(buf, int64(coord))
+		}
+		mult, err := poly.Multiplicity(wt).GobEncode()
+		if err != nil {
+			return nil, err
+		}
+		buf = appendUvarint(buf, uint64(len(mult)))
+		buf = append(buf, mult...)
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the contents of the polynomial with one encoded by MarshalBinary,
+// validating the algebra type and rank as UnmarshalJSON does.
+func (poly *hashPolyBuilder) UnmarshalBinary(data []byte) error {
+	rdr := bytes.NewReader(data)
+	version, err := rdr.ReadByte()
+	if err != nil {
+		return err
+	}
+	if version != polyEncodingVersion {
+		return fmt.Errorf("lie: unsupported encoding version %v", version)
+	}
+
+	typ, err := readBytes(rdr)
+	if err != nil {
+		return err
+	}
+	rank, err := binary.ReadUvarint(rdr)
+	if err != nil {
+		return err
+	}
+	if rank > math.MaxInt32 {
+		return fmt.Errorf("lie: invalid rank %v", rank)
+	}
+	rtsys, err := poly.decodeHeader(string(typ), int(rank))
+	if err != nil {
+		return err
+	}
+	vmap := util.NewVectorMap()
+
+	numTerms, err := binary.ReadUvarint(rdr)
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < numTerms; i++ {
+		wt, err := readWeight(rdr, rank)
+		if err != nil {
+			return err
+		}
+		multBytes, err := readBytes(rdr)
+		if err != nil {
+			return err
+		}
+		mult := big.NewInt(0)
+		if err := mult.GobDecode(multBytes); err != nil {
+			return err
+		}
+		addEncodedMonomial(vmap, wt, mult)
+	}
+	if rdr.Len() != 0 {
+		return errors.New("lie: trailing data after polynomial")
+	}
+
+	poly.rank, poly.vmap, poly.rtsys, poly.order = int(rank), vmap, rtsys, &keyOrder{}
+	return nil
+}
+
+// decodeHeader checks the encoded algebra type and rank against the polynomial and returns the root
+// system of the decoded polynomial. A polynomial without a root system adopts the encoded one.
+func (poly *hashPolyBuilder) decodeHeader(typ string, rank int) (RootSystem, error) {
+	if rank < 0 {
+		return nil, fmt.Errorf("lie: invalid rank %v", rank)
+	}
+
+	if poly.rtsys != nil {
+		if typ != "" && typ != poly.rtsys.CartanType() {
+			return nil, fmt.Errorf("lie: cannot decode type %v polynomial into type %v",
+				typ, poly.rtsys.CartanType())
+		}
+		if rank != poly.rank {
+			return nil, fmt.Errorf("lie: cannot decode rank %v polynomial into rank %v", rank, poly.rank)
+		}
+		return poly.rtsys, nil
+	}
+
+	switch typ {
+	case "":
+		return nil, nil
+	case "A":
+		return NewTypeARootSystem(rank), nil
+	default:
+		return nil, fmt.Errorf("lie: unknown algebra type %v", typ)
+	}
+}
+
+// addEncodedMonomial adds a decoded monomial to the map.
+func addEncodedMonomial(vmap util.VectorMap, wt Weight, mult *big.Int) {
+	if val, present := vmap.Get(wt); present {
+		val.(*big.Int).Add(val.(*big.Int), mult)
+		return
+	}
+	vmap.Put(wt, mult)
+}
+
+func appendUvarint(buf []byte, x uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	return append(buf, tmp[:n]...)
+}
+
+func appendVarint(buf []byte, x int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(tmp[:], x)
+	return append(buf, tmp[:n]...)
+}
+
+// readWeight reads the given number of varint Dynkin labels. The number is checked against the
+// remaining data before it is converted, so that a corrupt length cannot overflow.
+func readWeight(rdr *bytes.Reader, rank uint64) (Weight, error) {
+	if rank > uint64(rdr.Len()) {
+		return nil, errors.New("lie: truncated weight")
+	}
+	wt := make([]int, rank)
+	for i := range wt {
+		coord, err := binary.ReadVarint(rdr)
+		if err != nil {
+			return nil, err
+		}
+		wt[i] = int(coord)
+	}
+	return wt, nil
+}
+
+// readBytes reads a length-prefixed byte string.
+func readBytes(rdr *bytes.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(rdr)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(rdr.Len()) {
+		return nil, errors.New("lie: truncated data")
+	}
+	rslt := make([]byte, n)
+	_, err = rdr.Read(rslt)
+	return rslt, err
+}
@@ -0,0 +1,157 @@
+package lie
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestWeightEncoding(t *testing.T) {
+	cases := []struct {
+		wt       Weight
+		wantJSON string
+	}{
+		{Weight{}, "[]"},
+		{Weight{0}, "[0]"},
+		{Weight{1, 0, 2}, "[1,0,2]"},
+		{Weight{-3, 300}, "[-3,300]"},
+	}
+
+	for _, c := range cases {
+		data, err := json.Marshal(c.wt)
+		if err != nil || string(data) != c.wantJSON {
+			t.Errorf("json.Marshal(%v) = %s, %v, want %v", c.wt, data, err, c.wantJSON)
+		}
+		var gotJSON Weight
+		if err := json.Unmarshal(data, &gotJSON); err != nil || !gotJSON.Equals(c.wt) {
+			t.Errorf("json.Unmarshal(%s) = %v, %v, want %v", data, gotJSON, err, c.wt)
+		}
+
+		data, err = c.wt.MarshalBinary()
+		if err != nil {
+			t.Errorf("MarshalBinary(%v) returned error %v", c.wt, err)
+		}
+		var gotBin Weight
+		if err := gotBin.UnmarshalBinary(data); err != nil || !gotBin.Equals(c.wt) {
+			t.Errorf("UnmarshalBinary(%v) = %v, %v, want %v", data, gotBin, err, c.wt)
+		}
+	}
+}
+
+func TestPolyJSON(t *testing.T) {
+	alg := NewAlgebra(NewTypeARootSystem(2))
+	cases := []struct {
+		poly WeightPoly
+		want string
+	}{
+		{
+			NewWeightPolyBuilder(1),
+			`{"rank":1,"terms":[]}`,
+		},
+		{
+			alg.Tensor(Weight{1, 0}, Weight{0, 1}),
+			`{"type":"A","rank":2,"terms":[{"weight":[1,1],"mult":"1"},{"weight":[0,0],"mult":"1"}]}`,
+		},
+		{
+			alg.Tensor(Weight{1, 1}, Weight{1, 1}),
+			`{"type":"A","rank":2,"terms":[{"weight":[2,2],"mult":"1"},{"weight":[3,0],"mult":"1"},` +
+				`{"weight":[0,3],"mult":"1"},{"weight":[1,1],"mult":"2"},{"weight":[0,0],"mult":"1"}]}`,
+		},
+	}
+
+	for _, c := range cases {
+		data, err := json.Marshal(c.poly)
+		if err != nil || string(data) != c.want {
+			t.Errorf("json.Marshal(%v) = %s, %v, want %v", c.poly, data, err, c.want)
+		}
+
+		got := NewWeightPolyBuilder(0)
+		if err := json.Unmarshal(data, got); err != nil || !got.Equal(c.poly) {
+			t.Errorf("json.Unmarshal(%s) = %v, %v, want %v", data, got, err, c.poly)
+		}
+		if FormatPoly(got, DynkinFmt) != FormatPoly(c.poly, DynkinFmt) {
+			t.Errorf("Decoded poly %v does not preserve order of %v", got, c.poly)
+		}
+	}
+
+	bigPoly := NewWeightPolyBuilder(1)
+	json.Unmarshal([]byte(`{"rank":1,"terms":[{"weight":[2],"mult":"-123456789012345678901234567890"}]}`), bigPoly)
+	if got := bigPoly.Multiplicity(Weight{2}).String(); got != "-123456789012345678901234567890" {
+		t.Errorf("Decoded multiplicity = %v, want %v", got, "-123456789012345678901234567890")
+	}
+}
+
+func TestPolyBinary(t *testing.T) {
+	alg := NewAlgebra(NewTypeARootSystem(3))
+	largePoly := NewWeightPolyBuilder(2)
+	largePoly.SetMonomial(Weight{-1, 4}, alg.ReprDimension(Weight{10, 10, 10}))
+	largePoly.Mult(alg.ReprDimension(Weight{10, 10, 10}))
+	polys := []WeightPoly{
+		NewWeightPolyBuilder(0),
+		alg.Tensor(Weight{1, 0, 1}, Weight{0, 1, 0}),
+		alg.Fusion(2, Weight{1, 0, 1}, Weight{1, 0, 1}),
+		largePoly,
+	}
+
+	for _, poly := range polys {
+		data, err := poly.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Errorf("MarshalBinary(%v) returned error %v", poly, err)
+		}
+		got := NewWeightPolyBuilder(0)
+		if err := got.(*hashPolyBuilder).UnmarshalBinary(data); err != nil || !got.Equal(poly) {
+			t.Errorf("UnmarshalBinary(%v) = %v, %v, want %v", data, got, err, poly)
+		}
+		if err := got.(*hashPolyBuilder).UnmarshalBinary(data[:len(data)-1]); err == nil && len(data) > 1 {
+			t.Errorf("UnmarshalBinary of truncated data did not return an error")
+		}
+	}
+}
+
+func TestDecodeHugeLength(t *testing.T) {
+	for _, length := range []uint64{1 << 63, 1<<64 - 1, 1 << 40} {
+		data := appendUvarint(nil, length)
+		data = append(data, 2, 4)
+		var wt Weight
+		if err := wt.UnmarshalBinary(data); err == nil {
+			t.Errorf("UnmarshalBinary(%v) of weight = %v, want error", data, wt)
+		}
+
+		data = []byte{polyEncodingVersion, 0}
+		data = appendUvarint(data, length)
+		data = append(data, 1, 2, 4)
+		poly := NewWeightPolyBuilder(0)
+		if err := poly.(*hashPolyBuilder).UnmarshalBinary(data); err == nil {
+			t.Errorf("UnmarshalBinary(%v) of polynomial = %v, want error", data, poly)
+		}
+	}
+}
+
+func TestPolyDecodeCompatibility(t *testing.T) {
+	cases := []struct {
+		data    string
+		rank    int
+		wantErr bool
+	}{
+		{`{"type":"A","rank":2,"terms":[{"weight":[1,1],"mult":"1"}]}`, 2, false},
+		{`{"rank":2,"terms":[{"weight":[1,1],"mult":"1"}]}`, 2, false},
+		{`{"type":"A","rank":1,"terms":[{"weight":[1],"mult":"1"}]}`, 2, true},
+		{`{"type":"B","rank":2,"terms":[{"weight":[1,1],"mult":"1"}]}`, 2, true},
+		{`{"type":"A","rank":2,"terms":[{"weight":[1],"mult":"1"}]}`, 2, true},
+		{`{"type":"A","rank":2,"terms":[{"weight":[1,1],"mult":"one"}]}`, 2, true},
+	}
+
+	for _, c := range cases {
+		poly := NewAlgebra(NewTypeARootSystem(c.rank)).NewWeightPoly()
+		poly.SetMonomial(Weight{0, 0}, big.NewInt(1))
+		err := json.Unmarshal([]byte(c.data), poly)
+		if (err != nil) != c.wantErr {
+			t.Errorf("json.Unmarshal(%v) into rank %v returned error %v, want error %v",
+				c.data, c.rank, err, c.wantErr)
+		}
+		if err != nil && poly.Multiplicity(Weight{0, 0}).Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("Failed json.Unmarshal(%v) modified the polynomial", c.data)
+		}
+	}
+}
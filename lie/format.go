@@ -0,0 +1,81 @@
+package lie
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fmt selects the notation used to format weights.
+type Fmt int
+
+const (
+	// DynkinFmt formats weights by their Dynkin labels, e.g. [1,0,1].
+	DynkinFmt Fmt = iota
+	// FundamentalFmt formats weights as sums of fundamental weights, e.g. [ω1+ω3].
+	FundamentalFmt
+)
+
+// FormatWeight formats the given weight in the given notation.
+func FormatWeight(wt Weight, f Fmt) string {
+	terms := make([]string, 0, len(wt))
+	switch f {
+	case FundamentalFmt:
+		for i, coord := range wt {
+			if coord == 0 {
+				continue
+			}
+			term := fmt.Sprintf("ω%v", i+1)
+			switch coord {
+			case 1:
+			case -1:
+				term = "-" + term
+			default:
+				term = fmt.Sprintf("%v%v", coord, term)
+			}
+			if len(terms) > 0 && coord > 0 {
+				term = "+" + term
+			}
+			terms = append(terms, term)
+		}
+		if len(terms) == 0 {
+			terms = append(terms, "0")
+		}
+		return "[" + strings.Join(terms, "") + "]"
+	default:
+		for _, coord := range wt {
+			terms = append(terms, fmt.Sprint(coord))
+		}
+		return "[" + strings.Join(terms, ",") + "]"
+	}
+}
+
+// FormatPoly formats the given weight polynomial as a sum of weights with multiplicities, such as
+// 2*[1,0,1] + [0,0,0], in the given notation. Weights are listed in the order of WeightPoly.Weights.
+func FormatPoly(poly WeightPoly, f Fmt) string {
+	var sb strings.Builder
+	for i, wt := range poly.Weights() {
+		mult := poly.Multiplicity(wt)
+		coeff := mult.String()
+		if mult.Sign() < 0 {
+			coeff = coeff[1:]
+			if i == 0 {
+				sb.WriteString("-")
+			} else {
+				sb.WriteString(" - ")
+			}
+		} else if i > 0 {
+			sb.WriteString(" + ")
+		}
+
+		if coeff != "1" {
+			sb.WriteString(coeff)
+			sb.WriteString("*")
+		}
+		sb.WriteString(FormatWeight(wt, f))
+	}
+
+	if sb.Len() == 0 {
+		return "0"
+	}
+	return sb.String()
+}
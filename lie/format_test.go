@@ -0,0 +1,86 @@
+package lie
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFormatWeight(t *testing.T) {
+	cases := []struct {
+		wt   Weight
+		f    Fmt
+		want string
+	}{
+		{Weight{0}, DynkinFmt, "[0]"},
+		{Weight{1, 0, 1}, DynkinFmt, "[1,0,1]"},
+		{Weight{-1, 2}, DynkinFmt, "[-1,2]"},
+		{Weight{0, 0}, FundamentalFmt, "[0]"},
+		{Weight{1, 0, 1}, FundamentalFmt, "[ω1+ω3]"},
+		{Weight{2, 1, 0}, FundamentalFmt, "[2ω1+ω2]"},
+		{Weight{-1, 2}, FundamentalFmt, "[-ω1+2ω2]"},
+		{Weight{1, -2}, FundamentalFmt, "[ω1-2ω2]"},
+	}
+
+	for _, c := range cases {
+		got := FormatWeight(c.wt, c.f)
+		if got != c.want {
+			t.Errorf("FormatWeight(%v, %v) = %v, want %v", c.wt, c.f, got, c.want)
+		}
+	}
+}
+
+func TestFormatPoly(t *testing.T) {
+	cases := []struct {
+		rank int
+		ms   []monomial
+		f    Fmt
+		want string
+	}{
+		{1, []monomial{}, DynkinFmt, "0"},
+		{3, []monomial{{Weight{0, 0, 0}, 1}, {Weight{1, 0, 1}, 2}}, DynkinFmt, "2*[1,0,1] + [0,0,0]"},
+		{3, []monomial{{Weight{0, 0, 0}, 1}, {Weight{1, 0, 1}, 2}}, FundamentalFmt, "2*[ω1+ω3] + [0]"},
+		{2, []monomial{{Weight{0, 1}, -1}, {Weight{1, 0}, 3}}, DynkinFmt, "3*[1,0] - [0,1]"},
+		{2, []monomial{{Weight{0, 1}, 1}, {Weight{1, 0}, -3}}, DynkinFmt, "-3*[1,0] + [0,1]"},
+	}
+
+	for _, c := range cases {
+		poly := NewWeightPolyBuilder(c.rank)
+		for _, mono := range c.ms {
+			poly.SetMonomial(mono.wt, mono.mult())
+		}
+		got := FormatPoly(poly, c.f)
+		if got != c.want {
+			t.Errorf("FormatPoly(%v, %v) = %v, want %v", c.ms, c.f, got, c.want)
+		}
+	}
+
+	poly := NewWeightPolyBuilder(2)
+	poly.SetMonomial(Weight{1, 1}, big.NewInt(2))
+	if poly.(*hashPolyBuilder).String() != "2*[1,1]" {
+		t.Errorf("String() = %v, want %v", poly.(*hashPolyBuilder).String(), "2*[1,1]")
+	}
+}
+
+func TestCanonicalOrder(t *testing.T) {
+	cases := []struct {
+		rtsys RootSystem
+		wts   []Weight
+		want  string
+	}{
+		{typeA{1}, []Weight{Weight{1}, Weight{1}, Weight{1}}, "[3] + 2*[1]"},
+		{typeA{2}, []Weight{Weight{1, 1}, Weight{1, 1}}, "[2,2] + [3,0] + [0,3] + 2*[1,1] + [0,0]"},
+		{typeA{2}, []Weight{Weight{1, 0}, Weight{1, 1}, Weight{2, 0}},
+			"[4,1] + 2*[2,2] + 2*[3,0] + [0,3] + 3*[1,1] + [0,0]"},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(c.rtsys)
+		for i := 0; i < 5; i++ {
+			got := FormatPoly(alg.Tensor(c.wts...), DynkinFmt)
+			if got != c.want {
+				t.Errorf("Tensor(%v) = %v, want %v", c.wts, got, c.want)
+				break
+			}
+		}
+	}
+}
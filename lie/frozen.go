@@ -0,0 +1,68 @@
+package lie
+
+import (
+	"math/big"
+	"sort"
+)
+
+// frozenPoly is an immutable WeightPoly whose monomials are stored contiguously in canonical order.
+// Multiplicities are found by binary search and returned as copies, so a frozenPoly may be read
+// concurrently.
+type frozenPoly struct {
+	rank   int
+	rtsys  RootSystem
+	coords []int
+	wts    []Weight
+	mults  []big.Int
+}
+
+// newFrozenPoly copies the nonzero monomials of the given polynomial into a frozenPoly.
+func newFrozenPoly(poly hashPolyBuilder) *frozenPoly {
+	wts := poly.Weights()
+	frozen := &frozenPoly{
+		rank:   poly.rank,
+		rtsys:  poly.rtsys,
+		coords: make([]int, len(wts)*poly.rank),
+		wts:    make([]Weight, len(wts)),
+		mults:  make([]big.Int, len(wts)),
+	}
+	for i, wt := range wts {
+		frozen.wts[i] = frozen.coords[i*poly.rank : (i+1)*poly.rank : (i+1)*poly.rank]
+		copy(frozen.wts[i], wt)
+		frozen.mults[i].Set(poly.Multiplicity(wt))
+	}
+
+	return frozen
+}
+
+func (poly *frozenPoly) Rank() int {
+	return poly.rank
+}
+
+// Weights returns the weights with nonzero multiplicity in canonical order. The weights are shared
+// with the polynomial and must not be modified.
+func (poly *frozenPoly) Weights() []Weight {
+	retSlc := make([]Weight, len(poly.wts))
+	copy(retSlc, poly.wts)
+	return retSlc
+}
+
+// Multiplicity returns a copy of the multiplicity of the given weight.
+func (poly *frozenPoly) Multiplicity(wt Weight) *big.Int {
+	if len(wt) != poly.rank {
+		return big.NewInt(0)
+	}
+
+	i := sort.Search(len(poly.wts), func(i int) bool {
+		return compareWeights(poly.rtsys, poly.wts[i], wt) >= 0
+	})
+	if i < len(poly.wts) && poly.wts[i].Equals(wt) {
+		return big.NewInt(0).Set(&poly.mults[i])
+	}
+	return big.NewInt(0)
+}
+
+// String formats the polynomial in Dynkin label notation.
+func (poly *frozenPoly) String() string {
+	return FormatPoly(poly, DynkinFmt)
+}
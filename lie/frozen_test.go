@@ -0,0 +1,79 @@
+package lie
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	cases := []struct {
+		rank int
+		ms   []monomial
+		want string
+	}{
+		{1, []monomial{}, "0"},
+		{1, []monomial{{Weight{1}, 1}, {Weight{3}, 0}, {Weight{2}, -2}}, "-2*[2] + [1]"},
+		{2, []monomial{{Weight{0, 0}, 1}, {Weight{1, 1}, 2}, {Weight{0, 2}, 3}}, "2*[1,1] + 3*[0,2] + [0,0]"},
+	}
+
+	for _, c := range cases {
+		poly := NewWeightPolyBuilder(c.rank)
+		for _, mono := range c.ms {
+			poly.SetMonomial(mono.wt, mono.mult())
+		}
+		frozen := poly.Freeze()
+		poly.AddMonomial(Weight(make([]int, c.rank)), big.NewInt(5))
+
+		if got := FormatPoly(frozen, DynkinFmt); got != c.want {
+			t.Errorf("Freeze(%v) = %v, want %v", c.ms, got, c.want)
+		}
+		for _, mono := range c.ms {
+			got := frozen.Multiplicity(mono.wt)
+			if got.Cmp(mono.mult()) != 0 {
+				t.Errorf("Freeze(%v)[%v] = %v, want %v", c.ms, mono.wt, got, mono.mult())
+			}
+			got.SetInt64(100)
+			if frozen.Multiplicity(mono.wt).Cmp(mono.mult()) != 0 {
+				t.Errorf("Modifying multiplicity of %v changed frozen poly", mono.wt)
+			}
+		}
+		if got := frozen.Multiplicity(Weight{5, 5, 5}); got.Sign() != 0 {
+			t.Errorf("Freeze(%v)[%v] = %v, want 0", c.ms, Weight{5, 5, 5}, got)
+		}
+	}
+}
+
+func TestFrozenMultiplicity(t *testing.T) {
+	alg := NewAlgebra(NewTypeARootSystem(3))
+	frozen := alg.Tensor(Weight{1, 1, 0}, Weight{0, 1, 1}, Weight{1, 0, 1})
+	if _, ok := frozen.(*frozenPoly); !ok {
+		t.Fatalf("Tensor returned %T, want *frozenPoly", frozen)
+	}
+	want := NewWeightPolyBuilder(3)
+	want.Add(frozen)
+
+	var wg sync.WaitGroup
+	for _, wt := range alg.Weights(6) {
+		wg.Add(1)
+		go func(wt Weight) {
+			defer wg.Done()
+			if got := frozen.Multiplicity(wt); got.Cmp(want.Multiplicity(wt)) != 0 {
+				t.Errorf("Multiplicity(%v) = %v, want %v", wt, got, want.Multiplicity(wt))
+			}
+		}(wt)
+	}
+	wg.Wait()
+}
+
+func TestMemoizedKernelFreezes(t *testing.T) {
+	alg := NewAlgebra(NewTypeARootSystem(2))
+	prod := alg.FusionProduct(2)
+	prod.Apply(Weight{1, 0}, Weight{1, 1})
+
+	knl := prod.(polyProductImpl).productKernel.(*memoizedKernel)
+	val, _ := knl.lookup(Weight{1, 0}, Weight{1, 1})
+	if _, ok := val.(*frozenPoly); !ok {
+		t.Errorf("Memoized kernel stores %T, want *frozenPoly", val)
+	}
+}
@@ -0,0 +1,126 @@
+package lie
+
+import "math/big"
+
+// LevelRankDual maps a level ell weight of sl(r+1) to the level r+1 weight of sl(ell)
+// obtained by transposing its Young diagram and removing full columns of height ell. The
+// map is a bijection only modulo the simple currents of the two algebras. It returns nil if ell is
+// not positive or the weight is not a dominant weight of level at most ell.
+func LevelRankDual(ell int, wt Weight) Weight {
+	if ell < 1 || !isIntegrable(wt, ell) {
+		return nil
+	}
+
+	rslt := make([]int, ell-1)
+	row := 0
+	for i := len(wt) - 1; i >= 0; i-- {
+		row += wt[i]
+		if row > 0 && row < ell {
+			rslt[row-1]++
+		}
+	}
+
+	return rslt
+}
+
+// SimpleCurrent applies the k-th power of the generating simple current of sl(r+1) at level
+// ell to the given weight, i.e. rotates its extended Dynkin diagram labels k times.
+func SimpleCurrent(ell, k int, wt Weight) Weight {
+	rank := len(wt)
+	extWt := make([]int, rank+1)
+	extWt[0] = ell
+	for i := range wt {
+		extWt[i+1] = wt[i]
+		extWt[0] -= wt[i]
+	}
+
+	rslt := make([]int, rank)
+	k = mod(k, rank+1)
+	for i := range rslt {
+		rslt[i] = extWt[mod(i+1-k, rank+1)]
+	}
+
+	return rslt
+}
+
+// LevelRankRanks computes the rank of the genus zero conformal block of sl(r+1) at level ell
+// with the given weights, together with the rank of the level-rank dual conformal block of
+// sl(ell) at level r+1, where ell is at least two. The last dual weight is twisted by the
+// simple current correcting for the number of boxes of the weights; if the number of boxes
+// is not divisible by r+1, both blocks are taken to vanish. It returns nil ranks if ell is less
+// than two or the weights are not dominant weights of the same rank and level at most ell.
+func LevelRankRanks(ell int, wts ...Weight) (*big.Int, *big.Int) {
+	if ell < 2 {
+		return nil, nil
+	}
+	if len(wts) == 0 {
+		return big.NewInt(1), big.NewInt(1)
+	}
+
+	rank := len(wts[0])
+	for _, wt := range wts {
+		if len(wt) != rank || !isIntegrable(wt, ell) {
+			return nil, nil
+		}
+	}
+	alg := NewAlgebra(NewTypeARootSystem(rank))
+	dualAlg := NewAlgebra(NewTypeARootSystem(ell - 1))
+
+	lastWt := alg.Dual(wts[len(wts)-1])
+	fusionWts := make([]Weight, len(wts)-1)
+	dualWts := make([]Weight, len(wts)-1)
+	numBoxes := -boxes(lastWt)
+	for i := range fusionWts {
+		fusionWts[i] = wts[i]
+		dualWts[i] = LevelRankDual(ell, wts[i])
+		numBoxes += boxes(wts[i])
+	}
+
+	cbRank := fusionRank(alg, ell, fusionWts, lastWt)
+	if numBoxes%(rank+1) != 0 {
+		return cbRank, big.NewInt(0)
+	}
+
+	dualLastWt := SimpleCurrent(rank+1, numBoxes/(rank+1), LevelRankDual(ell, lastWt))
+	dualRank := fusionRank(dualAlg, rank+1, dualWts, dualLastWt)
+	return cbRank, dualRank
+}
+
+// CheckLevelRankDuality returns true if the conformal block ranks computed by LevelRankRanks agree,
+// and false if they are not defined.
+func CheckLevelRankDuality(ell int, wts ...Weight) bool {
+	cbRank, dualRank := LevelRankRanks(ell, wts...)
+	if cbRank == nil {
+		return false
+	}
+	return cbRank.Cmp(dualRank) == 0
+}
+
+// fusionRank computes the multiplicity of the given weight in the fusion product of the list of weights.
+func fusionRank(alg Algebra, ell int, wts []Weight, wt Weight) *big.Int {
+	if len(wts) == 0 {
+		if isDominant(wt) && alg.Level(wt) == 0 {
+			return big.NewInt(1)
+		}
+		return big.NewInt(0)
+	}
+
+	return big.NewInt(0).Set(alg.Fusion(ell, wts...).Multiplicity(wt))
+}
+
+// isIntegrable returns true if the type A weight is dominant of level at most ell.
+func isIntegrable(wt Weight, ell int) bool {
+	lv := 0
+	for i := range wt {
+		lv += wt[i]
+	}
+	return isDominant(wt) && lv <= ell
+}
+
+// boxes computes the number of boxes in the Young diagram of a type A weight.
+func boxes(wt Weight) (numBoxes int) {
+	for i := range wt {
+		numBoxes += (i + 1) * wt[i]
+	}
+	return
+}
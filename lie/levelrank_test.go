@@ -0,0 +1,129 @@
+package lie
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestLevelRankDual(t *testing.T) {
+	cases := []struct {
+		ell      int
+		wt, want Weight
+	}{
+		{2, Weight{0}, Weight{0}},
+		{2, Weight{1}, Weight{1}},
+		{2, Weight{2}, Weight{0}},
+		{3, Weight{1, 0}, Weight{1, 0}},
+		{3, Weight{0, 1}, Weight{2, 0}},
+		{3, Weight{2, 0}, Weight{0, 1}},
+		{3, Weight{1, 1}, Weight{1, 1}},
+		{3, Weight{0, 3}, Weight{0, 0}},
+		{3, Weight{1, 0, 1}, Weight{2, 1}},
+		{4, Weight{2, 1}, Weight{1, 0, 1}},
+		{0, Weight{0}, nil},
+		{2, Weight{3}, nil},
+		{3, Weight{2, -1}, nil},
+	}
+
+	for _, c := range cases {
+		got := LevelRankDual(c.ell, c.wt)
+		if !equals(got, c.want) {
+			t.Errorf("LevelRankDual(%v, %v) = %v, want %v", c.ell, c.wt, got, c.want)
+		}
+	}
+}
+
+func TestSimpleCurrent(t *testing.T) {
+	cases := []struct {
+		ell, k   int
+		wt, want Weight
+	}{
+		{2, 0, Weight{1}, Weight{1}},
+		{2, 1, Weight{0}, Weight{2}},
+		{2, 1, Weight{2}, Weight{0}},
+		{2, 2, Weight{2}, Weight{2}},
+		{3, 1, Weight{0, 0}, Weight{3, 0}},
+		{3, 1, Weight{1, 0}, Weight{2, 1}},
+		{3, 2, Weight{1, 0}, Weight{0, 2}},
+		{3, -1, Weight{1, 0}, Weight{0, 2}},
+	}
+
+	for _, c := range cases {
+		got := SimpleCurrent(c.ell, c.k, c.wt)
+		if !equals(got, c.want) {
+			t.Errorf("SimpleCurrent(%v, %v, %v) = %v, want %v", c.ell, c.k, c.wt, got, c.want)
+		}
+	}
+}
+
+func TestLevelRankRanks(t *testing.T) {
+	cases := []struct {
+		ell  int
+		wts  []Weight
+		want int
+	}{
+		{2, []Weight{Weight{1}, Weight{1}, Weight{2}}, 1},
+		{2, []Weight{Weight{1}, Weight{1}, Weight{1}, Weight{1}}, 2},
+		{3, []Weight{Weight{1, 0}, Weight{1, 0}, Weight{1, 0}}, 1},
+		{3, []Weight{Weight{1, 1}, Weight{1, 1}, Weight{1, 1}}, 2},
+		{2, []Weight{Weight{1, 0}, Weight{0, 1}, Weight{1, 1}, Weight{1, 1}}, 2},
+		{2, []Weight{Weight{1, 0, 0}, Weight{1, 0, 0}, Weight{0, 1, 0}}, 1},
+		{2, []Weight{Weight{1, 0}, Weight{1, 0}}, 0},
+	}
+
+	for _, c := range cases {
+		gotRank, gotDualRank := LevelRankRanks(c.ell, c.wts...)
+		want := big.NewInt(int64(c.want))
+		if gotRank.Cmp(want) != 0 || gotDualRank.Cmp(want) != 0 {
+			t.Errorf("LevelRankRanks(%v, %v) = %v, %v, want %v", c.ell, c.wts, gotRank, gotDualRank, c.want)
+		}
+	}
+}
+
+func TestLevelRankRanksInvalid(t *testing.T) {
+	cases := []struct {
+		ell int
+		wts []Weight
+	}{
+		{1, []Weight{Weight{1}, Weight{1}}},
+		{0, []Weight{}},
+		{2, []Weight{Weight{1}, Weight{3}}},
+		{2, []Weight{Weight{1, 0}, Weight{-1, 1}}},
+		{2, []Weight{Weight{1, 0}, Weight{1}}},
+	}
+
+	for _, c := range cases {
+		gotRank, gotDualRank := LevelRankRanks(c.ell, c.wts...)
+		if gotRank != nil || gotDualRank != nil {
+			t.Errorf("LevelRankRanks(%v, %v) = %v, %v, want nil, nil", c.ell, c.wts, gotRank, gotDualRank)
+		}
+		if CheckLevelRankDuality(c.ell, c.wts...) {
+			t.Errorf("CheckLevelRankDuality(%v, %v) = true, want false", c.ell, c.wts)
+		}
+	}
+}
+
+func TestCheckLevelRankDuality(t *testing.T) {
+	cases := []struct {
+		rank, ell int
+	}{
+		{1, 2},
+		{1, 3},
+		{2, 2},
+		{2, 3},
+		{3, 2},
+	}
+
+	for _, c := range cases {
+		wts := NewTypeARootSystem(c.rank).Weights(c.ell)
+		for _, wt1 := range wts {
+			for _, wt2 := range wts {
+				for _, wt3 := range wts {
+					if !CheckLevelRankDuality(c.ell, wt1, wt2, wt3) {
+						t.Errorf("CheckLevelRankDuality(%v, %v, %v, %v) = false", c.ell, wt1, wt2, wt3)
+					}
+				}
+			}
+		}
+	}
+}
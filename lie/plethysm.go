@@ -0,0 +1,153 @@
+package lie
+
+import "math/big"
+
+// SymmetricPower computes the decomposition of the k-th symmetric power of the irreducible
+// representation with the given highest weight. It returns nil for negative k.
+func (alg algebraImpl) SymmetricPower(wt Weight, k int) WeightPoly {
+	if k < 0 {
+		return nil
+	}
+	return alg.newtonPowers(wt, k, false)[k]
+}
+
+// ExteriorPower computes the decomposition of the k-th exterior power of the irreducible
+// representation with the given highest weight. It returns nil for negative k.
+func (alg algebraImpl) ExteriorPower(wt Weight, k int) WeightPoly {
+	if k < 0 {
+		return nil
+	}
+	return alg.newtonPowers(wt, k, true)[k]
+}
+
+// Plethysm computes the decomposition of the Schur functor S^μ applied to the irreducible
+// representation with the given highest weight, where μ is a partition listed in non-increasing
+// order. The Schur functor is expanded in symmetric powers by the Jacobi-Trudi identity
+// s_μ = det(h_{μ_i-i+j}). It returns nil if μ is not a partition.
+func (alg algebraImpl) Plethysm(wt Weight, partition []int) WeightPoly {
+	parts := make([]int, 0, len(partition))
+	for i, part := range partition {
+		if part < 0 || (i > 0 && part > partition[i-1]) {
+			return nil
+		}
+		if part > 0 {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return alg.trivialPoly()
+	}
+
+	prod := alg.newMemoizedProduct("tensor", alg.tensorProduct)
+	hs := alg.newtonPowers(wt, parts[0]+len(parts)-1, false)
+	minors := make(map[int]WeightPoly)
+
+	// minor computes the determinant of the rows following those already expanded, restricted to
+	// the columns not in the given set.
+	var minor func(used int) WeightPoly
+	minor = func(used int) WeightPoly {
+		row := 0
+		for mask := used; mask != 0; mask &= mask - 1 {
+			row++
+		}
+		if row == len(parts) {
+			return alg.trivialPoly()
+		}
+		if det, present := minors[used]; present {
+			return det
+		}
+
+		det := alg.NewWeightPoly()
+		sign := 1
+		for col := range parts {
+			if used&(1<<uint(col)) != 0 {
+				continue
+			}
+			if idx := parts[row] - row + col; idx >= 0 {
+				term := prod.Apply(hs[idx], minor(used|1<<uint(col)))
+				if sign > 0 {
+					det.Add(term)
+				} else {
+					det.Sub(term)
+				}
+			}
+			sign = -sign
+		}
+
+		minors[used] = det.Freeze()
+		return minors[used]
+	}
+
+	return minor(0)
+}
+
+// newtonPowers computes the symmetric powers h_0, ..., h_k of the irreducible representation with
+// the given highest weight from its Adams operations by the Newton identity
+// n h_n = Σ_{i=1}^n ψ^i h_{n-i}, or the exterior powers if alternating is true, in which case the
+// summands are weighted by the sign (-1)^(i-1).
+func (alg algebraImpl) newtonPowers(wt Weight, k int, alternating bool) []WeightPoly {
+	prod := alg.newMemoizedProduct("tensor", alg.tensorProduct)
+	adams := make([]WeightPoly, k+1)
+	powers := make([]WeightPoly, k+1)
+	powers[0] = alg.trivialPoly()
+	for n := 1; n <= k; n++ {
+		adams[n] = alg.Adams(n, wt)
+
+		sum := alg.NewWeightPoly()
+		for i := 1; i <= n; i++ {
+			term := prod.Apply(adams[i], powers[n-i])
+			if alternating && i%2 == 0 {
+				sum.Sub(term)
+			} else {
+				sum.Add(term)
+			}
+		}
+
+		power := alg.NewWeightPoly()
+		divisor := big.NewInt(int64(n))
+		for _, powerWt := range sum.Weights() {
+			power.SetMonomial(powerWt, big.NewInt(0).Quo(sum.Multiplicity(powerWt), divisor))
+		}
+		powers[n] = power.Freeze()
+	}
+
+	return powers
+}
+
+// Adams computes the decomposition of the k-th Adams operation applied to the given polynomial of
+// irreducible representations, i.e. of the virtual character obtained by scaling every weight of
+// the character by k. For negative k this is the dual of the -k-th Adams operation, and for k = 0
+// it is the trivial representation with multiplicity the dimension.
+func (alg algebraImpl) Adams(k int, poly WeightPoly) WeightPoly {
+	if k < 0 {
+		return alg.DualPoly(alg.Adams(-k, poly))
+	}
+	if k == 0 {
+		rslt := alg.NewWeightPoly()
+		rslt.SetMonomial(alg.NewWeight(), alg.PolyDimension(poly))
+		return rslt.Freeze()
+	}
+
+	domChar := alg.NewWeightPoly()
+	scaledWt := alg.NewWeight()
+	mult := big.NewInt(0)
+	for _, wt := range poly.Weights() {
+		char := alg.DominantChar(wt)
+		for _, charWt := range char.Weights() {
+			for i := range charWt {
+				scaledWt[i] = k * charWt[i]
+			}
+			mult.Mul(poly.Multiplicity(wt), char.Multiplicity(charWt))
+			domChar.AddMonomial(scaledWt, mult)
+		}
+	}
+
+	return alg.decomposeDominant(domChar)
+}
+
+// trivialPoly returns the polynomial of the trivial representation.
+func (alg algebraImpl) trivialPoly() WeightPoly {
+	rslt := alg.NewWeightPoly()
+	rslt.SetMonomial(alg.NewWeight(), big.NewInt(1))
+	return rslt.Freeze()
+}
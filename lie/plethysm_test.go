@@ -0,0 +1,179 @@
+package lie
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestAdams(t *testing.T) {
+	cases := []struct {
+		rank int
+		ms   []monomial
+		k    int
+		want string
+	}{
+		{1, []monomial{{Weight{1}, 1}}, 0, "2*[0]"},
+		{1, []monomial{{Weight{1}, 1}}, 1, "[1]"},
+		{1, []monomial{{Weight{1}, 1}}, 2, "[2] - [0]"},
+		{1, []monomial{{Weight{1}, 1}}, 3, "[3] - [1]"},
+		{1, []monomial{{Weight{2}, 1}}, 2, "[4] - [2] + [0]"},
+		{1, []monomial{{Weight{1}, 2}, {Weight{0}, -1}}, 2, "2*[2] - 3*[0]"},
+		{2, []monomial{{Weight{1, 0}, 1}}, 2, "[2,0] - [0,1]"},
+		{2, []monomial{{Weight{1, 0}, 1}}, -1, "[0,1]"},
+		{2, []monomial{{Weight{1, 0}, 1}}, -2, "[0,2] - [1,0]"},
+		{2, []monomial{{Weight{1, 1}, 1}}, 2, "[2,2] - [3,0] - [0,3] + [0,0]"},
+		{3, []monomial{{Weight{1, 0, 0}, 1}}, 3, "[3,0,0] - [1,1,0] + [0,0,1]"},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(NewTypeARootSystem(c.rank))
+		poly := alg.NewWeightPoly()
+		for _, mono := range c.ms {
+			poly.SetMonomial(mono.wt, mono.mult())
+		}
+		got := alg.Adams(c.k, poly)
+		if FormatPoly(got, DynkinFmt) != c.want {
+			t.Errorf("Adams(%v, %v) = %v, want %v", c.k, poly, got, c.want)
+		}
+		if dim, want := alg.PolyDimension(got), alg.PolyDimension(poly); dim.Cmp(want) != 0 {
+			t.Errorf("dim Adams(%v, %v) = %v, want %v", c.k, poly, dim, want)
+		}
+	}
+}
+
+func TestSymmetricPower(t *testing.T) {
+	cases := []struct {
+		rank int
+		wt   Weight
+		k    int
+		want string
+	}{
+		{1, Weight{1}, 0, "[0]"},
+		{1, Weight{1}, 1, "[1]"},
+		{1, Weight{1}, 4, "[4]"},
+		{1, Weight{2}, 2, "[4] + [0]"},
+		{1, Weight{2}, 3, "[6] + [2]"},
+		{2, Weight{1, 0}, 3, "[3,0]"},
+		{2, Weight{1, 1}, 2, "[2,2] + [1,1] + [0,0]"},
+		{2, Weight{1, 1}, 3, "[3,3] + [2,2] + [3,0] + [0,3] + [1,1] + [0,0]"},
+		{3, Weight{0, 1, 0}, 2, "[0,2,0] + [0,0,0]"},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(NewTypeARootSystem(c.rank))
+		got := alg.SymmetricPower(c.wt, c.k)
+		if FormatPoly(got, DynkinFmt) != c.want {
+			t.Errorf("SymmetricPower(%v, %v) = %v, want %v", c.wt, c.k, got, c.want)
+		}
+	}
+}
+
+func TestExteriorPower(t *testing.T) {
+	cases := []struct {
+		rank int
+		wt   Weight
+		k    int
+		want string
+	}{
+		{1, Weight{1}, 0, "[0]"},
+		{1, Weight{1}, 2, "[0]"},
+		{1, Weight{1}, 3, "0"},
+		{1, Weight{2}, 2, "[2]"},
+		{2, Weight{1, 0}, 2, "[0,1]"},
+		{2, Weight{1, 0}, 3, "[0,0]"},
+		{2, Weight{1, 1}, 2, "[3,0] + [0,3] + [1,1]"},
+		{3, Weight{1, 0, 0}, 2, "[0,1,0]"},
+		{3, Weight{0, 1, 0}, 2, "[1,0,1]"},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(NewTypeARootSystem(c.rank))
+		got := alg.ExteriorPower(c.wt, c.k)
+		if FormatPoly(got, DynkinFmt) != c.want {
+			t.Errorf("ExteriorPower(%v, %v) = %v, want %v", c.wt, c.k, got, c.want)
+		}
+	}
+}
+
+func TestPowerDimensions(t *testing.T) {
+	cases := []struct {
+		rank int
+		wt   Weight
+	}{
+		{1, Weight{3}},
+		{2, Weight{1, 1}},
+		{2, Weight{2, 0}},
+		{3, Weight{0, 1, 0}},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(NewTypeARootSystem(c.rank))
+		dim := alg.ReprDimension(c.wt).Int64()
+		for k := 0; k <= 4; k++ {
+			want := big.NewInt(0).Binomial(dim+int64(k)-1, int64(k))
+			if got := alg.PolyDimension(alg.SymmetricPower(c.wt, k)); got.Cmp(want) != 0 {
+				t.Errorf("dim SymmetricPower(%v, %v) = %v, want %v", c.wt, k, got, want)
+			}
+			want = big.NewInt(0).Binomial(dim, int64(k))
+			if got := alg.PolyDimension(alg.ExteriorPower(c.wt, k)); got.Cmp(want) != 0 {
+				t.Errorf("dim ExteriorPower(%v, %v) = %v, want %v", c.wt, k, got, want)
+			}
+		}
+
+		sum := alg.NewWeightPoly()
+		sum.Add(alg.SymmetricPower(c.wt, 2))
+		sum.Add(alg.ExteriorPower(c.wt, 2))
+		if want := alg.Tensor(c.wt, c.wt); !sum.Equal(want) {
+			t.Errorf("SymmetricPower(%v, 2) + ExteriorPower(%v, 2) = %v, want %v", c.wt, c.wt, sum, want)
+		}
+	}
+}
+
+func TestPlethysm(t *testing.T) {
+	cases := []struct {
+		rank      int
+		wt        Weight
+		partition []int
+		want      string
+	}{
+		{1, Weight{1}, []int{}, "[0]"},
+		{1, Weight{1}, []int{1, 2}, ""},
+		{1, Weight{1}, []int{2, -1}, ""},
+		{1, Weight{1}, []int{1, 1, 0}, "[0]"},
+		{1, Weight{2}, []int{2, 1}, "[4] + [2]"},
+		{2, Weight{1, 0}, []int{2, 1}, "[1,1]"},
+		{2, Weight{1, 0}, []int{2, 2}, "[0,2]"},
+		{2, Weight{1, 0}, []int{2, 1, 1}, "[1,0]"},
+		{3, Weight{1, 0, 0}, []int{2, 1}, "[1,1,0]"},
+		{3, Weight{1, 0, 0}, []int{3, 1, 1}, "[2,0,1]"},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(NewTypeARootSystem(c.rank))
+		got := alg.Plethysm(c.wt, c.partition)
+		if got == nil {
+			if c.want != "" {
+				t.Errorf("Plethysm(%v, %v) = nil, want %v", c.wt, c.partition, c.want)
+			}
+			continue
+		}
+		if FormatPoly(got, DynkinFmt) != c.want {
+			t.Errorf("Plethysm(%v, %v) = %v, want %v", c.wt, c.partition, got, c.want)
+		}
+	}
+
+	alg := NewAlgebra(NewTypeARootSystem(2))
+	for k := 1; k <= 3; k++ {
+		row, col := make([]int, 1), make([]int, k)
+		row[0] = k
+		for i := range col {
+			col[i] = 1
+		}
+		if got, want := alg.Plethysm(Weight{1, 1}, row), alg.SymmetricPower(Weight{1, 1}, k); FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+			t.Errorf("Plethysm([1,1], %v) = %v, want %v", row, got, want)
+		}
+		if got, want := alg.Plethysm(Weight{1, 1}, col), alg.ExteriorPower(Weight{1, 1}, k); FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+			t.Errorf("Plethysm([1,1], %v) = %v, want %v", col, got, want)
+		}
+	}
+}
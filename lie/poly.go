@@ -1,7 +1,10 @@
 package lie
 
 import (
+	"context"
 	"math/big"
+	"runtime"
+	"sort"
 	"sync"
 
 	"github.com/mjschust/lieprod/util"
@@ -14,32 +17,108 @@ type MutableWeightPoly interface {
 	SetMonomial(Weight, *big.Int)
 	AddMonomial(Weight, *big.Int)
 	Add(WeightPoly)
+	Sub(WeightPoly)
 	Mult(*big.Int)
+	Equal(WeightPoly) bool
+	IsZero() bool
+	Prune()
+	Freeze() WeightPoly
 }
 
 type hashPolyBuilder struct {
-	rank int
-	vmap util.VectorMap
+	rank  int
+	vmap  util.VectorMap
+	rtsys RootSystem
+	order *keyOrder
 }
 
-// NewWeightPolyBuilder constructs a new MutableWeightPoly.
+// keyOrder caches the keys of a polynomial's map in canonical order. The cache is invalidated
+// whenever a key is added to or removed from the map.
+type keyOrder struct {
+	sync.Mutex
+	keys  []Weight
+	valid bool
+}
+
+// NewWeightPolyBuilder constructs a new MutableWeightPoly. The returned polynomial can be
+// serialized with encoding/json and its binary encoding, and decoded into in place.
 func NewWeightPolyBuilder(rank int) MutableWeightPoly {
-	return hashPolyBuilder{rank, util.NewVectorMap()}
+	return &hashPolyBuilder{rank, util.NewVectorMap(), nil, &keyOrder{}}
+}
+
+// newPolyBuilder constructs a new MutableWeightPoly whose weights are ordered by the given root
+// system, which may be nil.
+func newPolyBuilder(rank int, rtsys RootSystem) MutableWeightPoly {
+	return &hashPolyBuilder{rank, util.NewVectorMap(), rtsys, &keyOrder{}}
 }
 
 func (poly hashPolyBuilder) Rank() int {
 	return poly.rank
 }
 
+// Weights returns the weights with nonzero multiplicity in canonical order.
 func (poly hashPolyBuilder) Weights() []Weight {
-	keys := poly.vmap.Keys()
-	retSlc := make([]Weight, len(keys))
-	for i, key := range keys {
-		retSlc[i] = key
+	keys := poly.sortedKeys()
+	retSlc := make([]Weight, 0, len(keys))
+	for _, key := range keys {
+		val, _ := poly.vmap.Get(key)
+		if val.(*big.Int).Sign() != 0 {
+			retSlc = append(retSlc, key)
+		}
 	}
 	return retSlc
 }
 
+// sortedKeys returns the keys of the underlying map in canonical order. The keys are only sorted
+// again after a key has been added or removed.
+func (poly hashPolyBuilder) sortedKeys() []Weight {
+	if poly.order == nil {
+		return sortKeys(poly.vmap.Keys(), poly.rtsys)
+	}
+
+	poly.order.Lock()
+	defer poly.order.Unlock()
+	if !poly.order.valid {
+		poly.order.keys = sortKeys(poly.vmap.Keys(), poly.rtsys)
+		poly.order.valid = true
+	}
+	return poly.order.keys
+}
+
+// invalidateOrder discards the cached order of the keys.
+func (poly hashPolyBuilder) invalidateOrder() {
+	if poly.order == nil {
+		return
+	}
+	poly.order.Lock()
+	poly.order.keys, poly.order.valid = nil, false
+	poly.order.Unlock()
+}
+
+func sortKeys(keys [][]int, rtsys RootSystem) []Weight {
+	rslt := make([]Weight, len(keys))
+	for i, key := range keys {
+		rslt[i] = key
+	}
+	sort.Slice(rslt, func(i, j int) bool { return compareWeights(rtsys, rslt[i], rslt[j]) < 0 })
+	return rslt
+}
+
+// compareWeights orders weights using the given root system. Without a root system, weights are
+// ordered as by the type A root system of the same rank, so a polynomial's order does not depend
+// on whether it is attached to one.
+func compareWeights(rtsys RootSystem, wt1, wt2 Weight) int {
+	if rtsys == nil {
+		rtsys = typeA{len(wt1)}
+	}
+	return rtsys.CompareWeights(wt1, wt2)
+}
+
+// String formats the polynomial in Dynkin label notation.
+func (poly hashPolyBuilder) String() string {
+	return FormatPoly(poly, DynkinFmt)
+}
+
 func (poly hashPolyBuilder) Multiplicity(wt Weight) *big.Int {
 	val, present := poly.vmap.Get(wt)
 	if present {
@@ -54,6 +133,7 @@ func (poly hashPolyBuilder) addWeight(wt Weight) Weight {
 		newWt := make([]int, poly.rank)
 		copy(newWt, wt)
 		poly.vmap.Put(newWt, big.NewInt(0))
+		poly.invalidateOrder()
 		return newWt
 	}
 	return wt
@@ -88,6 +168,14 @@ func (poly hashPolyBuilder) Add(poly2 WeightPoly) {
 	}
 }
 
+func (poly hashPolyBuilder) Sub(poly2 WeightPoly) {
+	negMult := big.NewInt(0)
+	for _, wt := range poly2.Weights() {
+		negMult.Neg(poly2.Multiplicity(wt))
+		poly.AddMonomial(wt, negMult)
+	}
+}
+
 func (poly hashPolyBuilder) Mult(val *big.Int) {
 	for _, wt := range poly.Weights() {
 		curVal, _ := poly.vmap.Get(wt)
@@ -95,44 +183,178 @@ func (poly hashPolyBuilder) Mult(val *big.Int) {
 	}
 }
 
+// Equal returns true if the given polynomial has the same multiplicities as this one.
+func (poly hashPolyBuilder) Equal(poly2 WeightPoly) bool {
+	if poly2.Rank() != poly.rank {
+		return false
+	}
+
+	for _, wt := range poly.Weights() {
+		if poly.Multiplicity(wt).Cmp(poly2.Multiplicity(wt)) != 0 {
+			return false
+		}
+	}
+	for _, wt := range poly2.Weights() {
+		if poly.Multiplicity(wt).Cmp(poly2.Multiplicity(wt)) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsZero returns true if every multiplicity of the polynomial is zero.
+func (poly hashPolyBuilder) IsZero() bool {
+	return len(poly.Weights()) == 0
+}
+
+// Prune removes the weights with zero multiplicity from the underlying map.
+func (poly hashPolyBuilder) Prune() {
+	removed := false
+	for _, wt := range poly.vmap.Keys() {
+		val, _ := poly.vmap.Get(wt)
+		if val.(*big.Int).Sign() == 0 {
+			poly.vmap.Remove(wt)
+			removed = true
+		}
+	}
+	if removed {
+		poly.invalidateOrder()
+	}
+}
+
+// Freeze returns an immutable copy of the polynomial. See frozenPoly.
+func (poly hashPolyBuilder) Freeze() WeightPoly {
+	return newFrozenPoly(poly)
+}
+
 // A WeightProduct defines a product on weights with polynomial output.
 type WeightProduct func(Weight, Weight) MutableWeightPoly
 
 // A PolyProduct defines a product on weight polynomials.
 type PolyProduct interface {
 	Apply(WeightPoly, WeightPoly) WeightPoly
+	ApplyContext(context.Context, WeightPoly, WeightPoly) (WeightPoly, error)
 	Reduce(...WeightPoly) WeightPoly
+	ReduceContext(context.Context, ...WeightPoly) (WeightPoly, error)
+	CacheStats() CacheStats
+}
+
+// A ProductOption configures a PolyProduct.
+type ProductOption func(*productConfig)
+
+type productConfig struct {
+	parallelism int
+	pool        *WorkerPool
+	cacheLimit  int64
+	store       *ProductStore
+	namespace   string
+}
+
+// WithParallelism sets the maximal number of polynomial multiplications Reduce runs concurrently.
+// The default is runtime.NumCPU(); values less than one are treated as one.
+func WithParallelism(n int) ProductOption {
+	return func(cfg *productConfig) {
+		if n < 1 {
+			n = 1
+		}
+		cfg.parallelism = n
+	}
+}
+
+// WithWorkerPool sets the pool computing the products of weights. By default, a pool of
+// runtime.NumCPU() goroutines shared by all products is used.
+func WithWorkerPool(pool *WorkerPool) ProductOption {
+	return func(cfg *productConfig) {
+		if pool != nil {
+			cfg.pool = pool
+		}
+	}
+}
+
+// WithCacheLimit bounds the cache of a memoized product by the total number of monomials of the
+// cached products of weights, with empty products counting as one monomial, evicting the least recently used products beyond it. A limit of zero,
+// the default, leaves the cache unbounded.
+func WithCacheLimit(limit int64) ProductOption {
+	return func(cfg *productConfig) {
+		if limit < 0 {
+			limit = 0
+		}
+		cfg.cacheLimit = limit
+	}
+}
+
+// WithStore backs the cache of a memoized product by the given persistent store. The namespace
+// identifies the product among the ones sharing the store, e.g. by algebra and level.
+func WithStore(store *ProductStore, namespace string) ProductOption {
+	return func(cfg *productConfig) {
+		cfg.store = store
+		cfg.namespace = namespace
+	}
 }
 
 // NewProduct constructs a poly product without memoization.
-func NewProduct(prod WeightProduct) PolyProduct {
-	return polyProductImpl{plainKernel{prod}}
+func NewProduct(prod WeightProduct, opts ...ProductOption) PolyProduct {
+	cfg := newProductConfig(opts)
+	return polyProductImpl{plainKernel{prod, cfg.pool}, cfg.parallelism}
 }
 
-// NewMemoizedProduct constructs a poly product with memoization.
-func NewMemoizedProduct(prod WeightProduct) PolyProduct {
-	return polyProductImpl{&memoizedKernel{prod, util.NewVectorMap(), sync.Mutex{}}}
+// NewMemoizedProduct constructs a poly product with memoization. The product of weights must be
+// commutative: the pair of weights is ordered before it is looked up, so prod(wt1, wt2) is cached
+// and returned for prod(wt2, wt1) as well.
+func NewMemoizedProduct(prod WeightProduct, opts ...ProductOption) PolyProduct {
+	cfg := newProductConfig(opts)
+	knl := &memoizedKernel{
+		prod:      prod,
+		pool:      cfg.pool,
+		cache:     newProductCache(cfg.cacheLimit),
+		pending:   util.NewVectorMap(),
+		store:     cfg.store,
+		namespace: cfg.namespace,
+	}
+	return polyProductImpl{knl, cfg.parallelism}
+}
+
+func newProductConfig(opts []ProductOption) productConfig {
+	cfg := productConfig{parallelism: runtime.NumCPU(), pool: defaultPool}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
 }
 
 type polyProductImpl struct {
 	productKernel
+	parallelism int
 }
 
 func (app polyProductImpl) Apply(poly1, poly2 WeightPoly) WeightPoly {
+	rslt, _ := app.ApplyContext(context.Background(), poly1, poly2)
+	return rslt
+}
+
+// ApplyContext computes the product of the given polynomials. If the context is done before the
+// product is computed, no further products of weights are started and the error of the context is
+// returned without waiting for the products of weights in progress.
+func (app polyProductImpl) ApplyContext(ctx context.Context, poly1, poly2 WeightPoly) (WeightPoly, error) {
 	type monoExpan struct {
 		coeff   *big.Int
 		promise polyPromise
 	}
-	retPoly := NewWeightPolyBuilder(poly1.Rank())
+	var retPoly MutableWeightPoly
 	poly1Wts := poly1.Weights()
 	poly2Wts := poly2.Weights()
 	expanList := make([]monoExpan, 0, len(poly1Wts)*len(poly2Wts))
+expand:
 	for _, wt1 := range poly1Wts {
 		mult1 := poly1.Multiplicity(wt1)
 		for _, wt2 := range poly2Wts {
+			if ctx.Err() != nil {
+				break expand
+			}
 			mult2 := poly2.Multiplicity(wt2)
 			coeff := big.NewInt(0).Mul(mult1, mult2)
-			promise := app.asynchApply(wt1, wt2)
+			promise := app.asynchApply(ctx, wt1, wt2)
 			expanList = append(expanList, monoExpan{coeff, promise})
 		}
 	}
@@ -141,6 +363,12 @@ func (app polyProductImpl) Apply(poly1, poly2 WeightPoly) WeightPoly {
 	for _, exp := range expanList {
 		coeff := exp.coeff
 		summand := exp.promise()
+		if summand == nil || ctx.Err() != nil {
+			continue
+		}
+		if retPoly == nil {
+			retPoly = newPolyBuilder(poly1.Rank(), polyRootSystem(summand))
+		}
 
 		for _, wt := range summand.Weights() {
 			mult := summand.Multiplicity(wt)
@@ -149,102 +377,271 @@ func (app polyProductImpl) Apply(poly1, poly2 WeightPoly) WeightPoly {
 		}
 	}
 
-	return retPoly
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if retPoly == nil {
+		return newPolyBuilder(poly1.Rank(), polyRootSystem(poly1)).Freeze(), nil
+	}
+	return retPoly.Freeze(), nil
+}
+
+// polyRootSystem returns the root system ordering the weights of the given polynomial, if any.
+func polyRootSystem(poly WeightPoly) RootSystem {
+	switch p := poly.(type) {
+	case *hashPolyBuilder:
+		return p.rtsys
+	case *frozenPoly:
+		return p.rtsys
+	}
+	return nil
+}
+
+// CacheStats returns the statistics of the cache of a memoized product, or zero statistics for a
+// product without memoization.
+func (app polyProductImpl) CacheStats() CacheStats {
+	return app.cacheStats()
 }
 
 func (app polyProductImpl) Reduce(polys ...WeightPoly) WeightPoly {
+	rslt, _ := app.ReduceContext(context.Background(), polys...)
+	return rslt
+}
+
+// ReduceContext computes the product of the given polynomials. The product is assumed to be
+// commutative and associative, so the polynomials are multiplied in a balanced tree, with up to the
+// configured number of multiplications of each level of the tree running concurrently. If the
+// context is done, the multiplications in progress are waited for and the error of the context is
+// returned.
+func (app polyProductImpl) ReduceContext(ctx context.Context, polys ...WeightPoly) (WeightPoly, error) {
 	if len(polys) == 0 {
+		return nil, nil
+	}
+
+	sem := make(chan struct{}, app.parallelism)
+	for len(polys) > 1 {
+		next := make([]WeightPoly, (len(polys)+1)/2)
+		var wg sync.WaitGroup
+	pairs:
+		for i := 0; i+1 < len(polys); i += 2 {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				break pairs
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				next[i/2], _ = app.ApplyContext(ctx, polys[i], polys[i+1])
+				<-sem
+			}(i)
+		}
+		if len(polys)%2 == 1 {
+			next[len(next)-1] = polys[len(polys)-1]
+		}
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		polys = next
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return polys[0], nil
+}
+
+// Power computes the k-th power of the given polynomial with respect to the product by repeated
+// squaring. The zeroth power is the zero weight, and nil is returned for negative k.
+func Power(prod PolyProduct, poly WeightPoly, k int) WeightPoly {
+	return FilteredPower(prod, poly, k, nil)
+}
+
+// FilteredPower computes the k-th power of the given polynomial like Power, but discards the weights
+// not satisfying the predicate after every multiplication and from the first power. This computes the power correctly if the
+// weights failing the predicate span an ideal, e.g. the weights of level greater than ell for the
+// level ell fusion product. A nil predicate keeps every weight.
+func FilteredPower(prod PolyProduct, poly WeightPoly, k int, pred func(Weight) bool) WeightPoly {
+	if k < 0 {
 		return nil
 	}
-	if len(polys) == 1 {
-		return polys[0]
+	apply := func(poly1, poly2 WeightPoly) WeightPoly {
+		rslt := prod.Apply(poly1, poly2)
+		if pred != nil {
+			rslt = Filter(rslt, pred)
+		}
+		return rslt
+	}
+
+	if k == 1 && pred != nil {
+		// The first power is not the result of a multiplication.
+		return Filter(poly, pred)
 	}
 
-	var product = polys[0]
-	for i := 1; i < len(polys); i++ {
-		product = app.Apply(product, polys[i])
+	var rslt WeightPoly
+	base := poly
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			if rslt == nil {
+				rslt = base
+			} else {
+				rslt = apply(rslt, base)
+			}
+		}
+		if k > 1 {
+			base = apply(base, base)
+		}
 	}
 
-	return product
+	if rslt == nil {
+		return Weight(make([]int, poly.Rank()))
+	}
+	return rslt
 }
 
 type productKernel interface {
-	asynchApply(Weight, Weight) polyPromise
+	asynchApply(context.Context, Weight, Weight) polyPromise
+	cacheStats() CacheStats
 }
 
+// A polyPromise waits for a product of weights. It returns nil if the product was skipped because
+// its context was done.
 type polyPromise func() WeightPoly
 
 type plainKernel struct {
 	prod WeightProduct
+	pool *WorkerPool
 }
 
-func (knl plainKernel) asynchApply(wt1, wt2 Weight) polyPromise {
-	c := make(chan WeightPoly)
-	go func(wt1, wt2 Weight, c chan WeightPoly) {
+func (knl plainKernel) cacheStats() CacheStats {
+	return CacheStats{}
+}
+
+func (knl plainKernel) asynchApply(ctx context.Context, wt1, wt2 Weight) polyPromise {
+	c := make(chan WeightPoly, 1)
+	knl.pool.submit(func() {
+		if ctx.Err() != nil {
+			c <- nil
+			return
+		}
 		c <- knl.prod(wt1, wt2)
-	}(wt1, wt2, c)
+	})
 
 	return func() WeightPoly {
-		rslt := <-c
-		return rslt
+		select {
+		case rslt := <-c:
+			return rslt
+		case <-ctx.Done():
+			return nil
+		}
 	}
 }
 
+// memoizedKernel caches the products of weights. Since products are commutative, the pair of
+// weights is put in canonical order before consulting the cache, and concurrent requests for the
+// same uncached pair share a single computation. Products missing from the cache are looked up in
+// the persistent store, if any, before being computed, and computed products are added to it.
 type memoizedKernel struct {
-	prod     WeightProduct
-	rsltDict util.VectorMap
+	prod      WeightProduct
+	pool      *WorkerPool
+	cache     *productCache
+	pending   util.VectorMap
+	store     *ProductStore
+	namespace string
 	sync.Mutex
 }
 
-func (knl *memoizedKernel) asynchApply(wt1, wt2 Weight) polyPromise {
+// pendingProduct is a product of weights in progress. The result is nil if the computation was
+// skipped because its context was done.
+type pendingProduct struct {
+	done chan struct{}
+	rslt WeightPoly
+}
+
+// wait returns the result of the computation, or nil if the context is done first.
+func (call *pendingProduct) wait(ctx context.Context) WeightPoly {
+	select {
+	case <-call.done:
+		return call.rslt
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+func (knl *memoizedKernel) asynchApply(ctx context.Context, wt1, wt2 Weight) polyPromise {
+	if compareLex(wt1, wt2) > 0 {
+		wt1, wt2 = wt2, wt1
+	}
+	key := pairKey(wt1, wt2)
+
 	knl.Lock()
-	submap, present := knl.rsltDict.Get(wt1)
-	if present {
-		curryMap := submap.(util.VectorMap)
-		val, present := curryMap.Get(wt2)
-		if present {
-			knl.Unlock()
-			return func() WeightPoly {
-				return val.(WeightPoly)
-			}
+	if rslt, present := knl.cache.get(key); present {
+		knl.Unlock()
+		return func() WeightPoly {
+			return rslt
 		}
+	}
 
+	if val, present := knl.pending.Get(key); present {
 		knl.Unlock()
-		c := make(chan WeightPoly)
-		go func(wt1, wt2 Weight, c chan WeightPoly) {
-			c <- knl.prod(wt1, wt2)
-		}(wt1, wt2, c)
-
+		call := val.(*pendingProduct)
 		return func() WeightPoly {
-			rslt := <-c
-			knl.Lock()
-			curryMap.Put(wt2, rslt)
-			knl.Unlock()
+			rslt := call.wait(ctx)
+			if rslt == nil && ctx.Err() == nil {
+				// The computation was abandoned by a cancelled request; retry it.
+				return knl.asynchApply(ctx, wt1, wt2)()
+			}
 			return rslt
 		}
 	}
 
+	call := &pendingProduct{done: make(chan struct{})}
+	knl.pending.Put(key, call)
 	knl.Unlock()
-	c := make(chan WeightPoly)
-	go func(wt1, wt2 Weight, c chan WeightPoly) {
-		c <- knl.prod(wt1, wt2)
-	}(wt1, wt2, c)
 
-	return func() WeightPoly {
-		rslt := <-c
-		knl.Lock()
-		submap, present := knl.rsltDict.Get(wt1)
-		if present {
-			curryMap := submap.(util.VectorMap)
-			curryMap.Put(wt2, rslt)
-			knl.Unlock()
-			return rslt
+	knl.pool.submit(func() {
+		var rslt WeightPoly
+		stored := false
+		if knl.store != nil {
+			rslt, stored = knl.store.get(knl.namespace, wt1, wt2)
+		}
+		if !stored && ctx.Err() == nil {
+			rslt = knl.prod(wt1, wt2).Freeze()
+		}
+
+		if rslt != nil && !stored && knl.store != nil {
+			knl.store.put(knl.namespace, wt1, wt2, rslt)
 		}
 
-		curryMap := util.NewVectorMap()
-		curryMap.Put(wt2, rslt)
-		knl.rsltDict.Put(wt1, curryMap)
+		knl.Lock()
+		knl.pending.Remove(key)
+		if rslt != nil {
+			knl.cache.put(key, rslt)
+		}
+		call.rslt = rslt
 		knl.Unlock()
-		return rslt
+		close(call.done)
+	})
+
+	return func() WeightPoly {
+		return call.wait(ctx)
 	}
 }
+
+// lookup returns the cached product of the given weights, if present.
+func (knl *memoizedKernel) lookup(wt1, wt2 Weight) (WeightPoly, bool) {
+	if compareLex(wt1, wt2) > 0 {
+		wt1, wt2 = wt2, wt1
+	}
+
+	knl.Lock()
+	defer knl.Unlock()
+	return knl.cache.peek(pairKey(wt1, wt2))
+}
+
+func (knl *memoizedKernel) cacheStats() CacheStats {
+	knl.Lock()
+	defer knl.Unlock()
+	return knl.cache.stats
+}
@@ -1,8 +1,14 @@
 package lie
 
 import (
+	"context"
+	"fmt"
 	"math/big"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestEmptyPoly(t *testing.T) {
@@ -139,3 +145,366 @@ func TestSingleWeightPoly(t *testing.T) {
 			poly, Weight{1, 2}, gotMult, 0)
 	}
 }
+
+func TestSubPoly(t *testing.T) {
+	cases := []struct {
+		rank       int
+		ms1, ms2   []monomial
+		want       []monomial
+		wantNumWts int
+	}{
+		{
+			1,
+			[]monomial{{Weight{1}, 1}},
+			[]monomial{{Weight{1}, 1}},
+			[]monomial{{Weight{1}, 0}},
+			0,
+		},
+		{
+			1,
+			[]monomial{{Weight{1}, 2}, {Weight{2}, 2}},
+			[]monomial{{Weight{1}, 1}, {Weight{3}, 1}},
+			[]monomial{{Weight{1}, 1}, {Weight{2}, 2}, {Weight{3}, -1}},
+			3,
+		},
+		{
+			2,
+			[]monomial{{Weight{1, 1}, 1}, {Weight{0, 0}, 2}},
+			[]monomial{{Weight{0, 0}, 2}},
+			[]monomial{{Weight{1, 1}, 1}, {Weight{0, 0}, 0}},
+			1,
+		},
+	}
+
+	for _, c := range cases {
+		poly1 := NewWeightPolyBuilder(c.rank)
+		for _, mono := range c.ms1 {
+			poly1.AddMonomial(mono.wt, mono.mult())
+		}
+		poly2 := NewWeightPolyBuilder(c.rank)
+		for _, mono := range c.ms2 {
+			poly2.AddMonomial(mono.wt, mono.mult())
+		}
+		poly1.Sub(poly2)
+
+		for _, mono := range c.want {
+			gotMult := poly1.Multiplicity(mono.wt)
+			if gotMult.Cmp(mono.mult()) != 0 {
+				t.Errorf("poly[%v] = %v, want %v", mono.wt, gotMult, mono.mult())
+			}
+		}
+		if len(poly1.Weights()) != c.wantNumWts {
+			t.Errorf("poly.Weights() = %v, want %v weights", poly1.Weights(), c.wantNumWts)
+		}
+		for _, wt := range poly1.Weights() {
+			if poly1.Multiplicity(wt).Sign() == 0 {
+				t.Errorf("poly.Weights() contains %v with zero multiplicity", wt)
+			}
+		}
+	}
+}
+
+func TestEqualPoly(t *testing.T) {
+	cases := []struct {
+		rank     int
+		ms1, ms2 []monomial
+		want     bool
+	}{
+		{1, []monomial{}, []monomial{}, true},
+		{1, []monomial{{Weight{1}, 0}}, []monomial{}, true},
+		{1, []monomial{{Weight{1}, 1}}, []monomial{}, false},
+		{1, []monomial{}, []monomial{{Weight{1}, 1}}, false},
+		{1, []monomial{{Weight{1}, 1}, {Weight{0}, 2}}, []monomial{{Weight{0}, 2}, {Weight{1}, 1}}, true},
+		{1, []monomial{{Weight{1}, 1}, {Weight{0}, 2}}, []monomial{{Weight{0}, 1}, {Weight{1}, 1}}, false},
+		{2, []monomial{{Weight{1, 0}, 3}, {Weight{0, 1}, 0}}, []monomial{{Weight{1, 0}, 3}}, true},
+	}
+
+	for _, c := range cases {
+		poly1 := NewWeightPolyBuilder(c.rank)
+		for _, mono := range c.ms1 {
+			poly1.AddMonomial(mono.wt, mono.mult())
+		}
+		poly2 := NewWeightPolyBuilder(c.rank)
+		for _, mono := range c.ms2 {
+			poly2.AddMonomial(mono.wt, mono.mult())
+		}
+
+		if got := poly1.Equal(poly2); got != c.want {
+			t.Errorf("%v.Equal(%v) = %v, want %v", c.ms1, c.ms2, got, c.want)
+		}
+		if got := poly2.Equal(poly1); got != c.want {
+			t.Errorf("%v.Equal(%v) = %v, want %v", c.ms2, c.ms1, got, c.want)
+		}
+	}
+
+	poly := NewWeightPolyBuilder(1)
+	poly.AddMonomial(Weight{2}, big.NewInt(1))
+	if !poly.Equal(Weight{2}) {
+		t.Errorf("%v.Equal(%v) = false, want true", poly.Weights(), Weight{2})
+	}
+}
+
+func TestPrunePoly(t *testing.T) {
+	poly := NewWeightPolyBuilder(1)
+	poly.AddMonomial(Weight{1}, big.NewInt(1))
+	poly.AddMonomial(Weight{2}, big.NewInt(1))
+	poly.AddMonomial(Weight{1}, big.NewInt(-1))
+	if poly.IsZero() {
+		t.Errorf("IsZero() = true for nonzero poly")
+	}
+
+	poly.Prune()
+	hashPoly := poly.(*hashPolyBuilder)
+	if hashPoly.vmap.Size() != 1 {
+		t.Errorf("Pruned poly contains %v entries, want 1", hashPoly.vmap.Size())
+	}
+
+	poly.AddMonomial(Weight{2}, big.NewInt(-1))
+	if !poly.IsZero() {
+		t.Errorf("IsZero() = false for zero poly")
+	}
+	poly.Prune()
+	if hashPoly.vmap.Size() != 0 {
+		t.Errorf("Pruned poly contains %v entries, want 0", hashPoly.vmap.Size())
+	}
+}
+
+func TestWeightsOrder(t *testing.T) {
+	wts := []Weight{{0, 1}, {2, 0}, {1, 1}, {0, 0}, {0, 3}, {1, 0}}
+	poly := NewWeightPolyBuilder(2)
+	attached := newPolyBuilder(2, typeA{2})
+	for _, wt := range wts {
+		poly.AddMonomial(wt, big.NewInt(1))
+		attached.AddMonomial(wt, big.NewInt(1))
+		if got, want := fmt.Sprint(poly.Weights()), fmt.Sprint(attached.Weights()); got != want {
+			t.Errorf("Weights() = %v without a root system, want %v", got, want)
+		}
+	}
+
+	poly.AddMonomial(Weight{0, 3}, big.NewInt(-1))
+	poly.Prune()
+	poly.AddMonomial(Weight{3, 0}, big.NewInt(1))
+	want := "[[3 0] [2 0] [1 1] [1 0] [0 1] [0 0]]"
+	if got := fmt.Sprint(poly.Weights()); got != want {
+		t.Errorf("Weights() = %v, want %v", got, want)
+	}
+	if got := fmt.Sprint(poly.Freeze().Weights()); got != want {
+		t.Errorf("Freeze().Weights() = %v, want %v", got, want)
+	}
+}
+
+func TestPower(t *testing.T) {
+	cases := []struct {
+		alg Algebra
+		wt  Weight
+		k   int
+	}{
+		{NewAlgebra(NewTypeARootSystem(1)), Weight{1}, 0},
+		{NewAlgebra(NewTypeARootSystem(1)), Weight{1}, 1},
+		{NewAlgebra(NewTypeARootSystem(1)), Weight{1}, 5},
+		{NewAlgebra(NewTypeARootSystem(2)), Weight{1, 0}, 6},
+		{NewAlgebra(NewTypeARootSystem(2)), Weight{1, 1}, 4},
+		{NewAlgebra(NewTypeARootSystem(3)), Weight{0, 1, 0}, 7},
+	}
+
+	for _, c := range cases {
+		wts := make([]Weight, c.k)
+		for i := range wts {
+			wts[i] = c.wt
+		}
+		want := NewWeightPolyBuilder(c.wt.Rank())
+		if c.k > 0 {
+			want.Add(c.alg.Tensor(wts...))
+		} else {
+			want.Add(Weight(make([]int, c.wt.Rank())))
+		}
+
+		got := Power(c.alg.TensorProduct(), c.wt, c.k)
+		if !want.Equal(got) {
+			t.Errorf("Power(%v, %v) = %v, want %v", c.wt, c.k, got, want)
+		}
+
+		for ell := 1; ell <= 3 && c.k > 0; ell++ {
+			got := FilteredPower(c.alg.FusionProduct(ell), c.wt, c.k, func(wt Weight) bool {
+				return c.alg.Level(wt) <= ell
+			})
+			wantPoly := NewWeightPolyBuilder(c.wt.Rank())
+			wantPoly.Add(c.alg.Fusion(ell, wts...))
+			if !wantPoly.Equal(got) {
+				t.Errorf("FilteredPower(%v, %v) at level %v = %v, want %v", c.wt, c.k, ell, got, wantPoly)
+			}
+		}
+	}
+
+	alg := NewAlgebra(NewTypeARootSystem(1))
+	poly := NewWeightPolyBuilder(1)
+	poly.AddMonomial(Weight{1}, big.NewInt(1))
+	poly.AddMonomial(Weight{3}, big.NewInt(2))
+	got := FilteredPower(alg.FusionProduct(2), poly, 1, func(wt Weight) bool { return alg.Level(wt) <= 2 })
+	if FormatPoly(got, DynkinFmt) != "[1]" {
+		t.Errorf("FilteredPower(%v, 1) at level 2 = %v, want [1]", poly, got)
+	}
+
+	if got := Power(NewAlgebra(NewTypeARootSystem(1)).TensorProduct(), Weight{1}, -1); got != nil {
+		t.Errorf("Power(%v, -1) = %v, want nil", Weight{1}, got)
+	}
+}
+
+func TestParallelReduce(t *testing.T) {
+	alg := algebraImpl{RootSystem: typeA{2}}
+	wts := []Weight{{1, 0}, {0, 1}, {1, 1}, {2, 0}, {1, 0}, {0, 2}, {1, 1}}
+	polys := make([]WeightPoly, len(wts))
+	for i := range wts {
+		polys[i] = wts[i]
+	}
+
+	for n := 1; n <= len(polys); n++ {
+		for _, ell := range []int{2, 4} {
+			want := polys[0]
+			seqProd := NewProduct(func(wt1, wt2 Weight) MutableWeightPoly {
+				return alg.fusionProduct(ell, wt1, wt2)
+			})
+			for i := 1; i < n; i++ {
+				want = seqProd.Apply(want, polys[i])
+			}
+
+			for _, parallelism := range []int{0, 1, 2, 8} {
+				prod := NewMemoizedProduct(func(wt1, wt2 Weight) MutableWeightPoly {
+					return alg.fusionProduct(ell, wt1, wt2)
+				}, WithParallelism(parallelism))
+				got := prod.Reduce(polys[:n]...)
+				if FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+					t.Errorf("Reduce(%v) at level %v with parallelism %v = %v, want %v",
+						wts[:n], ell, parallelism, got, want)
+				}
+			}
+		}
+	}
+
+	if got := NewProduct(alg.tensorProduct).Reduce(); got != nil {
+		t.Errorf("Reduce() = %v, want nil", got)
+	}
+}
+
+func TestAlgebraProductParallelism(t *testing.T) {
+	cases := []struct {
+		opts []AlgebraOption
+		want int
+	}{
+		{nil, runtime.NumCPU()},
+		{[]AlgebraOption{WithProductParallelism(3)}, 3},
+		{[]AlgebraOption{WithProductParallelism(0)}, 1},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(NewTypeARootSystem(2), c.opts...)
+		prods := []PolyProduct{alg.FusionProduct(2), alg.TensorProduct()}
+		for _, prod := range prods {
+			if got := prod.(polyProductImpl).parallelism; got != c.want {
+				t.Errorf("Product parallelism = %v, want %v", got, c.want)
+			}
+		}
+	}
+}
+
+func TestReduceContext(t *testing.T) {
+	alg := algebraImpl{RootSystem: typeA{2}}
+	prod := alg.FusionProduct(3)
+	polys := []WeightPoly{Weight{1, 1}, Weight{2, 0}, Weight{1, 1}, Weight{0, 3}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got, err := prod.ReduceContext(ctx, polys...); got != nil || err != context.Canceled {
+		t.Errorf("ReduceContext with cancelled context = %v, %v, want nil, %v", got, err, context.Canceled)
+	}
+	if got, err := prod.ApplyContext(ctx, polys[0], polys[1]); got != nil || err != context.Canceled {
+		t.Errorf("ApplyContext with cancelled context = %v, %v, want nil, %v", got, err, context.Canceled)
+	}
+
+	// Cancelled products must not be memoized.
+	want := alg.Fusion(3, Weight{1, 1}, Weight{2, 0}, Weight{1, 1}, Weight{0, 3})
+	got, err := prod.ReduceContext(context.Background(), polys...)
+	if err != nil || FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+		t.Errorf("ReduceContext(%v) = %v, %v, want %v", polys, got, err, want)
+	}
+	if got, err := prod.ReduceContext(context.Background()); got != nil || err != nil {
+		t.Errorf("ReduceContext() = %v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestMemoizedWaitContext(t *testing.T) {
+	alg := algebraImpl{RootSystem: typeA{2}}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	prod := NewMemoizedProduct(func(wt1, wt2 Weight) MutableWeightPoly {
+		close(started)
+		<-release
+		return alg.fusionProduct(2, wt1, wt2)
+	})
+
+	done := make(chan WeightPoly)
+	go func() {
+		done <- prod.Apply(Weight{1, 0}, Weight{0, 1})
+	}()
+	<-started
+
+	// A request waiting for another request's product returns when its own deadline passes.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	waited := make(chan error)
+	go func() {
+		_, err := prod.ApplyContext(ctx, Weight{0, 1}, Weight{1, 0})
+		waited <- err
+	}()
+	select {
+	case err := <-waited:
+		if err != context.DeadlineExceeded {
+			t.Errorf("ApplyContext past its deadline returned error %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("ApplyContext waited for a pending product past its deadline")
+	}
+
+	close(release)
+	if got, want := <-done, alg.Fusion(2, Weight{1, 0}, Weight{0, 1}); FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+		t.Errorf("Apply([1,0], [0,1]) = %v, want %v", got, want)
+	}
+}
+
+func TestMemoizedDeduplication(t *testing.T) {
+	alg := algebraImpl{RootSystem: typeA{2}}
+	var calls int32
+	prod := NewMemoizedProduct(func(wt1, wt2 Weight) MutableWeightPoly {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(5 * time.Millisecond)
+		return alg.fusionProduct(2, wt1, wt2)
+	})
+
+	poly := NewWeightPolyBuilder(2)
+	poly.SetMonomial(Weight{1, 0}, big.NewInt(1))
+	poly.SetMonomial(Weight{0, 1}, big.NewInt(2))
+	want := alg.FusionProduct(2).Apply(poly, poly)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := prod.Apply(poly, poly); FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+				t.Errorf("Apply(%v, %v) = %v, want %v", poly, poly, got, want)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The pairs ([1,0], [0,1]) and ([0,1], [1,0]) share a computation.
+	if calls != 3 {
+		t.Errorf("Memoized product computed %v products, want 3", calls)
+	}
+
+	prod.Apply(Weight{0, 1}, Weight{1, 0})
+	prod.Apply(Weight{1, 0}, Weight{0, 1})
+	if calls != 3 {
+		t.Errorf("Memoized product computed %v products after cached lookups, want 3", calls)
+	}
+}
@@ -0,0 +1,41 @@
+package lie
+
+import "runtime"
+
+// A WorkerPool bounds the number of goroutines computing products of weights. Products created
+// from the same Algebra share its pool.
+type WorkerPool struct {
+	sem chan struct{}
+}
+
+// defaultPool is shared by the products and algebras constructed without a pool.
+var defaultPool = NewWorkerPool(0)
+
+// NewWorkerPool constructs a pool running at most the given number of goroutines. If the size is
+// less than one, runtime.NumCPU() is used.
+func NewWorkerPool(size int) *WorkerPool {
+	if size < 1 {
+		size = runtime.NumCPU()
+	}
+	return &WorkerPool{make(chan struct{}, size)}
+}
+
+// Size returns the maximal number of goroutines of the pool.
+func (pool *WorkerPool) Size() int {
+	return cap(pool.sem)
+}
+
+// submit runs the given task on a new goroutine if the pool has a free slot, and otherwise runs it
+// in the calling goroutine. Running saturated tasks in place bounds the number of goroutines
+// without blocking, so tasks may themselves submit tasks to the pool without deadlocking.
+func (pool *WorkerPool) submit(task func()) {
+	select {
+	case pool.sem <- struct{}{}:
+		go func() {
+			defer func() { <-pool.sem }()
+			task()
+		}()
+	default:
+		task()
+	}
+}
@@ -0,0 +1,92 @@
+package lie
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPoolSize(t *testing.T) {
+	cases := []struct {
+		size, want int
+	}{
+		{1, 1},
+		{4, 4},
+		{0, runtime.NumCPU()},
+		{-1, runtime.NumCPU()},
+	}
+
+	for _, c := range cases {
+		if got := NewWorkerPool(c.size).Size(); got != c.want {
+			t.Errorf("NewWorkerPool(%v).Size() = %v, want %v", c.size, got, c.want)
+		}
+	}
+}
+
+func TestWorkerPoolBound(t *testing.T) {
+	for _, size := range []int{1, 2, 4} {
+		pool := NewWorkerPool(size)
+		var running, maxRunning int32
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			pool.submit(func() {
+				defer wg.Done()
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			})
+		}
+		wg.Wait()
+
+		// The submitting goroutine runs tasks itself when the pool is saturated.
+		if maxRunning > int32(size+1) {
+			t.Errorf("Pool of size %v ran %v tasks concurrently", size, maxRunning)
+		}
+	}
+}
+
+func TestWorkerPoolNested(t *testing.T) {
+	pool := NewWorkerPool(1)
+	alg := NewAlgebra(NewTypeARootSystem(2), WithProductPool(pool))
+	prod := NewProduct(func(wt1, wt2 Weight) MutableWeightPoly {
+		rslt := alg.NewWeightPoly()
+		rslt.Add(alg.Tensor(wt1, wt2, wt1))
+		return rslt
+	}, WithWorkerPool(pool))
+
+	done := make(chan WeightPoly)
+	go func() {
+		done <- prod.Reduce(Weight{1, 0}, Weight{0, 1}, Weight{1, 1})
+	}()
+	select {
+	case got := <-done:
+		if got == nil || len(got.Weights()) == 0 {
+			t.Errorf("Nested product returned %v", got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Nested product deadlocked")
+	}
+}
+
+func TestAlgebraProductPool(t *testing.T) {
+	wts := []Weight{{1, 0, 1}, {0, 1, 0}, {1, 1, 0}, {0, 0, 2}}
+	want := NewAlgebra(NewTypeARootSystem(3))
+	for _, size := range []int{1, 3} {
+		alg := NewAlgebra(NewTypeARootSystem(3), WithProductPool(NewWorkerPool(size)))
+		if got := alg.Tensor(wts...); FormatPoly(got, DynkinFmt) != FormatPoly(want.Tensor(wts...), DynkinFmt) {
+			t.Errorf("Tensor(%v) with pool of size %v = %v, want %v", wts, size, got, want.Tensor(wts...))
+		}
+		if got := alg.Fusion(3, wts...); FormatPoly(got, DynkinFmt) != FormatPoly(want.Fusion(3, wts...), DynkinFmt) {
+			t.Errorf("Fusion(3, %v) with pool of size %v = %v, want %v", wts, size, got, want.Fusion(3, wts...))
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package lie
+
+import "math/big"
+
+// PolyDimension computes the total dimension of the representation described by the given
+// polynomial, i.e. the sum of the dimensions of the irreducible representations weighted by
+// their multiplicities.
+func (alg algebraImpl) PolyDimension(poly WeightPoly) *big.Int {
+	rslt := big.NewInt(0)
+	term := big.NewInt(0)
+	for _, wt := range poly.Weights() {
+		term.Mul(poly.Multiplicity(wt), alg.ReprDimension(wt))
+		rslt.Add(rslt, term)
+	}
+	return rslt
+}
+
+// DualPoly computes the polynomial of the dual representation by dualizing every weight.
+func (alg algebraImpl) DualPoly(poly WeightPoly) WeightPoly {
+	rslt := alg.NewWeightPoly()
+	for _, wt := range poly.Weights() {
+		rslt.AddMonomial(alg.Dual(wt), poly.Multiplicity(wt))
+	}
+	return rslt.Freeze()
+}
+
+// Truncate removes the weights of level greater than ell from the given polynomial.
+func (alg algebraImpl) Truncate(ell int, poly WeightPoly) WeightPoly {
+	rslt := alg.NewWeightPoly()
+	for _, wt := range poly.Weights() {
+		if alg.Level(wt) <= ell {
+			rslt.AddMonomial(wt, poly.Multiplicity(wt))
+		}
+	}
+	return rslt.Freeze()
+}
+
+// Filter returns the polynomial consisting of the monomials of the given polynomial whose weights
+// satisfy the predicate.
+func Filter(poly WeightPoly, pred func(Weight) bool) WeightPoly {
+	rslt := newPolyBuilder(poly.Rank(), polyRootSystem(poly))
+	for _, wt := range poly.Weights() {
+		if pred(wt) {
+			rslt.AddMonomial(wt, poly.Multiplicity(wt))
+		}
+	}
+	return rslt.Freeze()
+}
@@ -0,0 +1,100 @@
+package lie
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPolyDimension(t *testing.T) {
+	cases := []struct {
+		alg  Algebra
+		wts  []Weight
+		want int64
+	}{
+		{NewAlgebra(NewTypeARootSystem(1)), []Weight{{1}}, 2},
+		{NewAlgebra(NewTypeARootSystem(1)), []Weight{{1}, {1}, {1}}, 8},
+		{NewAlgebra(NewTypeARootSystem(2)), []Weight{{1, 1}, {1, 1}}, 64},
+		{NewAlgebra(NewTypeARootSystem(3)), []Weight{{1, 0, 0}, {0, 1, 0}, {2, 0, 1}}, 4 * 6 * 36},
+	}
+
+	for _, c := range cases {
+		got := c.alg.PolyDimension(c.alg.Tensor(c.wts...))
+		if got.Cmp(big.NewInt(c.want)) != 0 {
+			t.Errorf("PolyDimension(Tensor(%v)) = %v, want %v", c.wts, got, c.want)
+		}
+	}
+
+	alg := NewAlgebra(NewTypeARootSystem(2))
+	poly := alg.NewWeightPoly()
+	poly.SetMonomial(Weight{1, 0}, big.NewInt(2))
+	poly.SetMonomial(Weight{0, 0}, big.NewInt(-1))
+	if got := alg.PolyDimension(poly); got.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("PolyDimension(%v) = %v, want 5", poly, got)
+	}
+}
+
+func TestDualPoly(t *testing.T) {
+	cases := []struct {
+		alg  Algebra
+		wts  []Weight
+		want string
+	}{
+		{NewAlgebra(NewTypeARootSystem(1)), []Weight{{1}, {2}}, "[3] + [1]"},
+		{NewAlgebra(NewTypeARootSystem(2)), []Weight{{1, 0}, {1, 0}}, "[0,2] + [1,0]"},
+		{NewAlgebra(NewTypeARootSystem(3)), []Weight{{1, 0, 0}, {0, 1, 0}}, "[0,1,1] + [1,0,0]"},
+	}
+
+	for _, c := range cases {
+		poly := c.alg.Tensor(c.wts...)
+		got := c.alg.DualPoly(poly)
+		if FormatPoly(got, DynkinFmt) != c.want {
+			t.Errorf("DualPoly(%v) = %v, want %v", poly, got, c.want)
+		}
+		if back := c.alg.DualPoly(got); FormatPoly(back, DynkinFmt) != FormatPoly(poly, DynkinFmt) {
+			t.Errorf("DualPoly(DualPoly(%v)) = %v, want %v", poly, back, poly)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		alg  Algebra
+		ell  int
+		wts  []Weight
+		want string
+	}{
+		{NewAlgebra(NewTypeARootSystem(1)), 2, []Weight{{1}, {1}, {1}}, "2*[1]"},
+		{NewAlgebra(NewTypeARootSystem(1)), 0, []Weight{{1}, {1}}, "[0]"},
+		{NewAlgebra(NewTypeARootSystem(2)), 2, []Weight{{1, 1}, {1, 1}}, "2*[1,1] + [0,0]"},
+		{NewAlgebra(NewTypeARootSystem(2)), 1, []Weight{{1, 1}, {1, 1}}, "[0,0]"},
+		{NewAlgebra(NewTypeARootSystem(2)), -1, []Weight{{1, 1}, {1, 1}}, "0"},
+	}
+
+	for _, c := range cases {
+		got := c.alg.Truncate(c.ell, c.alg.Tensor(c.wts...))
+		if FormatPoly(got, DynkinFmt) != c.want {
+			t.Errorf("Truncate(%v, Tensor(%v)) = %v, want %v", c.ell, c.wts, got, c.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	alg := NewAlgebra(NewTypeARootSystem(2))
+	poly := alg.Tensor(Weight{1, 1}, Weight{1, 1})
+	cases := []struct {
+		pred func(Weight) bool
+		want string
+	}{
+		{func(Weight) bool { return true }, "[2,2] + [3,0] + [0,3] + 2*[1,1] + [0,0]"},
+		{func(Weight) bool { return false }, "0"},
+		{func(wt Weight) bool { return wt[0] == wt[1] }, "[2,2] + 2*[1,1] + [0,0]"},
+		{func(wt Weight) bool { return alg.ReprDimension(wt).Cmp(big.NewInt(10)) == 0 }, "[3,0] + [0,3]"},
+	}
+
+	for i, c := range cases {
+		got := Filter(poly, c.pred)
+		if FormatPoly(got, DynkinFmt) != c.want {
+			t.Errorf("Filter case %v = %v, want %v", i, got, c.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package lie
 
 // RootSystem contains type-specific Lie algebra operations.
 type RootSystem interface {
+	CartanType() string
 	Rank() int
 	DualCoxeter() int
 	PositiveRoots() []Root
@@ -14,6 +15,7 @@ type RootSystem interface {
 	Rho() Weight
 	Level(Weight) int
 	Dual(Weight) Weight
+	CompareWeights(Weight, Weight) int
 	reflectToChamber(Weight, Weight) int
 	reflectEpcToChamber(epCoord) int
 	reflectEpcToAlcove(epCoord, int) int
@@ -34,6 +36,11 @@ type typeA struct {
 	rank int
 }
 
+// CartanType returns the letter of the root system in the Cartan-Killing classification.
+func (rtsys typeA) CartanType() string {
+	return "A"
+}
+
 // Rank returns the rank of the root system.
 func (rtsys typeA) Rank() int {
 	return rtsys.rank
@@ -170,6 +177,27 @@ func (rtsys typeA) Dual(wt Weight) Weight {
 	return rslt
 }
 
+// CompareWeights compares weights in the canonical order: by level, then by height (a linear extension
+// of the dominance order), then lexicographically, with higher weights first. It returns a negative
+// number if wt1 precedes wt2, a positive number if wt2 precedes wt1, and zero if they are equal.
+func (rtsys typeA) CompareWeights(wt1, wt2 Weight) int {
+	if lv1, lv2 := rtsys.Level(wt1), rtsys.Level(wt2); lv1 != lv2 {
+		return lv2 - lv1
+	}
+	if ht1, ht2 := rtsys.height(wt1), rtsys.height(wt2); ht1 != ht2 {
+		return ht2 - ht1
+	}
+	return compareLex(wt1, wt2)
+}
+
+// height computes twice the pairing of the weight with the sum of the fundamental coweights.
+func (rtsys typeA) height(wt Weight) (ht int) {
+	for i := range wt {
+		ht += (i + 1) * (rtsys.rank - i) * wt[i]
+	}
+	return
+}
+
 // ReflectToChamber reflects the given weight into the dominant chamber and returns
 // the result with reflection parity.
 func (rtsys typeA) reflectToChamber(wt Weight, rslt Weight) int {
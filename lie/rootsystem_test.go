@@ -313,6 +313,31 @@ func TestTypeADual(t *testing.T) {
 	}
 }
 
+func TestTypeACompareWeights(t *testing.T) {
+	cases := []struct {
+		rtsys    RootSystem
+		wt1, wt2 Weight
+		want     int
+	}{
+		{typeA{1}, Weight{1}, Weight{1}, 0},
+		{typeA{1}, Weight{2}, Weight{1}, -1},
+		{typeA{1}, Weight{0}, Weight{1}, 1},
+		{typeA{2}, Weight{1, 1}, Weight{3, 0}, 1},
+		{typeA{2}, Weight{3, 0}, Weight{0, 3}, -1},
+		{typeA{2}, Weight{2, 1}, Weight{1, 2}, -1},
+		{typeA{2}, Weight{0, 0}, Weight{1, 0}, 1},
+		{typeA{3}, Weight{0, 2, 0}, Weight{1, 0, 1}, -1},
+		{typeA{3}, Weight{2, 0, 0}, Weight{0, 1, 1}, 1},
+	}
+
+	for _, c := range cases {
+		got := c.rtsys.CompareWeights(c.wt1, c.wt2)
+		if (got < 0) != (c.want < 0) || (got > 0) != (c.want > 0) {
+			t.Errorf("CompareWeights(%v, %v) = %v, want sign %v", c.wt1, c.wt2, got, c.want)
+		}
+	}
+}
+
 func TestTypeAReflectIntoChamber(t *testing.T) {
 	cases := []struct {
 		rtsys    RootSystem
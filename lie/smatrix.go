@@ -0,0 +1,67 @@
+package lie
+
+import "math/big"
+
+// SMatrixEntry computes the level ell S-matrix entry of the given integrable weights by the
+// Kac-Peterson formula, up to normalization. The returned cyclotomic integer is the sum
+// Σ_w ε(w) exp(-2πi (w(λ+ρ), μ+ρ) / (ell+h)) over the Weyl group, an element of Z[ζ]
+// with ζ of order KillingFactor()*(ell+DualCoxeter()). The true S-matrix
+// entry is obtained by multiplying by a constant c with |c|² = 1/(|P/Q|(ell+h)^rank), which
+// is independent of the weights.
+func (alg algebraImpl) SMatrixEntry(ell int, wt1, wt2 Weight) Cyclotomic {
+	order := alg.KillingFactor() * (ell + alg.DualCoxeter())
+	rslt := NewCyclotomic(order)
+
+	rho := alg.Rho()
+	shiftedWt1 := alg.NewWeight()
+	shiftedWt1.AddWeights(wt1, rho)
+	shiftedWt2 := alg.NewWeight()
+	shiftedWt2.AddWeights(wt2, rho)
+
+	orbitEpc := alg.newEpc()
+	alg.convertWeightToEpc(shiftedWt1, orbitEpc)
+	parityEpc := alg.newEpc()
+	orbitWt := alg.NewWeight()
+	sign := big.NewInt(0)
+	done := false
+	for ; !done; done = alg.nextOrbitEpc(orbitEpc) {
+		copy(parityEpc, orbitEpc)
+		parity := alg.reflectEpcToChamber(parityEpc)
+		alg.convertEpCoord(orbitEpc, orbitWt)
+		power := mod(-alg.IntKillingForm(orbitWt, shiftedWt2), order)
+		sign.SetInt64(int64(parity))
+		rslt.coeffs[power].Add(rslt.coeffs[power], sign)
+	}
+
+	return rslt
+}
+
+// GaloisAction computes the action of the Galois automorphism ζ -> ζ^a on the level ell
+// integrable weights, where a is coprime to the order of the S-matrix entries. It returns the
+// weight σ(λ) and the sign ε(λ) satisfying σ(SMatrixEntry(ell, λ, μ)) = ε(λ) SMatrixEntry(ell, σ(λ), μ)
+// for every μ. If a is not coprime to the order, a(λ+ρ) may lie on a wall of the alcove; in this
+// case nil and a sign of zero are returned.
+func (alg algebraImpl) GaloisAction(ell, a int, wt Weight) (Weight, int) {
+	alcoveLevel := ell + alg.DualCoxeter()
+	order := alg.KillingFactor() * alcoveLevel
+	a = mod(a, order)
+
+	rho := alg.newEpc()
+	alg.convertWeightToEpc(alg.Rho(), rho)
+	epc := alg.newEpc()
+	alg.convertWeightToEpc(wt, epc)
+	epc.addEpc(epc, rho)
+	for i := range epc {
+		epc[i] *= a
+	}
+
+	parity := alg.reflectEpcToAlcove(epc, alcoveLevel)
+	epc.subEpc(epc, rho)
+	rslt := alg.NewWeight()
+	alg.convertEpCoord(epc, rslt)
+	if !isDominant(rslt) || alg.Level(rslt) > ell {
+		return nil, 0
+	}
+
+	return rslt, parity
+}
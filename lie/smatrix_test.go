@@ -0,0 +1,131 @@
+package lie
+
+import (
+	"math/big"
+	"math/cmplx"
+	"testing"
+)
+
+func TestSMatrixEntry(t *testing.T) {
+	cases := []struct {
+		rtsys    RootSystem
+		ell      int
+		wt1, wt2 Weight
+		want     complex128
+	}{
+		{typeA{1}, 1, Weight{0}, Weight{0}, -1.7320508075688772i},
+		{typeA{1}, 1, Weight{0}, Weight{1}, -1.7320508075688772i},
+		{typeA{1}, 1, Weight{1}, Weight{1}, 1.7320508075688772i},
+		{typeA{1}, 2, Weight{1}, Weight{1}, 0},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(c.rtsys)
+		got := alg.SMatrixEntry(c.ell, c.wt1, c.wt2).Complex()
+		if cmplx.Abs(got-c.want) > 1e-9 {
+			t.Errorf("SMatrixEntry(%v, %v, %v) = %v, want %v", c.ell, c.wt1, c.wt2, got, c.want)
+		}
+	}
+}
+
+func TestSMatrixUnitarity(t *testing.T) {
+	cases := []struct {
+		rtsys RootSystem
+		ell   int
+	}{
+		{typeA{1}, 1},
+		{typeA{1}, 3},
+		{typeA{2}, 1},
+		{typeA{2}, 2},
+		{typeA{3}, 2},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(c.rtsys)
+		wts := alg.Weights(c.ell)
+		norm := big.NewInt(int64(alg.KillingFactor()))
+		alcoveLevel := big.NewInt(int64(c.ell + alg.DualCoxeter()))
+		for i := 0; i < alg.Rank(); i++ {
+			norm.Mul(norm, alcoveLevel)
+		}
+		for _, wt1 := range wts {
+			for _, wt2 := range wts {
+				sum := NewCyclotomic(1)
+				for _, wt3 := range wts {
+					sum = sum.Add(alg.SMatrixEntry(c.ell, wt1, wt3).Mul(alg.SMatrixEntry(c.ell, wt2, wt3).Conj()))
+				}
+				want := NewCyclotomic(1)
+				if wt1.Equals(wt2) {
+					want.coeffs[0].Set(norm)
+				}
+				if !sum.Equals(want) {
+					t.Errorf("SMatrix(%v, %v) rows %v, %v not orthogonal: %v", c.rtsys, c.ell, wt1, wt2, sum.Complex())
+				}
+			}
+		}
+	}
+}
+
+func TestGaloisAction(t *testing.T) {
+	cases := []struct {
+		rtsys    RootSystem
+		ell, a   int
+		wt       Weight
+		want     Weight
+		wantSign int
+	}{
+		{typeA{1}, 2, 1, Weight{1}, Weight{1}, 1},
+		{typeA{1}, 2, 3, Weight{0}, Weight{2}, 1},
+		{typeA{1}, 2, 3, Weight{1}, Weight{1}, -1},
+		{typeA{1}, 2, 3, Weight{2}, Weight{0}, 1},
+		{typeA{1}, 2, 2, Weight{1}, nil, 0},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(c.rtsys)
+		got, gotSign := alg.GaloisAction(c.ell, c.a, c.wt)
+		if !equals(got, c.want) || gotSign != c.wantSign {
+			t.Errorf("GaloisAction(%v, %v, %v) = %v, %v, want %v, %v",
+				c.ell, c.a, c.wt, got, gotSign, c.want, c.wantSign)
+		}
+	}
+}
+
+func TestGaloisSymmetry(t *testing.T) {
+	cases := []struct {
+		rtsys RootSystem
+		ell   int
+	}{
+		{typeA{1}, 3},
+		{typeA{2}, 2},
+		{typeA{3}, 1},
+	}
+
+	for _, c := range cases {
+		alg := NewAlgebra(c.rtsys)
+		wts := alg.Weights(c.ell)
+		order := alg.KillingFactor() * (c.ell + alg.DualCoxeter())
+		for a := 1; a < order; a++ {
+			if gcd(a, order) != 1 {
+				continue
+			}
+			for _, wt1 := range wts {
+				galoisWt, sign := alg.GaloisAction(c.ell, a, wt1)
+				if sign == 0 {
+					t.Errorf("GaloisAction(%v, %v, %v) lies on an alcove wall", c.ell, a, wt1)
+					continue
+				}
+				for _, wt2 := range wts {
+					got := alg.SMatrixEntry(c.ell, wt1, wt2).Galois(a)
+					want := alg.SMatrixEntry(c.ell, galoisWt, wt2)
+					if sign < 0 {
+						want = NewCyclotomic(order).Sub(want)
+					}
+					if !got.Equals(want) {
+						t.Errorf("σ_%v(S[%v, %v]) != %v S[%v, %v]", a, wt1, wt2, sign, galoisWt, wt2)
+					}
+				}
+			}
+		}
+	}
+}
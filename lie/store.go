@@ -0,0 +1,235 @@
+package lie
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/mjschust/lieprod/util"
+)
+
+// recordHeaderLen is the length of the header of a store record: the lengths of the key and value,
+// the checksum of the lengths, and the checksum of the key and value, each as a 4-byte big-endian
+// integer.
+const recordHeaderLen = 16
+
+// errIncompleteRecord marks a record extending past the end of the file.
+var errIncompleteRecord = errors.New("lie: incomplete record")
+
+// A ProductStore persists products of weights in an append-only log file, so that memoized
+// products can reuse results computed by earlier processes. Each record holds a key, consisting of
+// a namespace identifying the product and the pair of weights, and the binary encoding of the
+// product. Only the keys and the positions of the products are kept in memory; products are read
+// from the file when they are requested.
+//
+// A ProductStore is safe for concurrent use, and several processes may share the same file: the
+// file is locked while it is loaded and while a record is appended, and the records appended by
+// other processes are indexed before each append. On platforms without advisory file locks, the
+// file must be used by one process at a time.
+type ProductStore struct {
+	mu    sync.Mutex
+	file  *os.File
+	index map[string]storedValue
+	end   int64
+	err   error
+}
+
+// storedValue is the position of a stored product in the file.
+type storedValue struct {
+	off int64
+	len int
+}
+
+// OpenProductStore opens the store in the given file, creating it if necessary, and indexes the
+// products it contains. A record left incomplete at the end of the file by an interrupted write is
+// discarded; any other invalid record is reported as an error.
+func OpenProductStore(path string) (*ProductStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	store := &ProductStore{file: file, index: make(map[string]storedValue)}
+	err = store.update()
+	if unlockErr := unlockFile(file); err == nil {
+		err = unlockErr
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// Len returns the number of stored products.
+func (store *ProductStore) Len() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return len(store.index)
+}
+
+// Err returns the first error encountered while writing to the store, if any.
+func (store *ProductStore) Err() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.err
+}
+
+// Close closes the file of the store.
+func (store *ProductStore) Close() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.file.Close()
+}
+
+// get returns the stored product of the given weights in the namespace, if present.
+func (store *ProductStore) get(namespace string, wt1, wt2 Weight) (WeightPoly, bool) {
+	store.mu.Lock()
+	val, present := store.index[storeKey(namespace, wt1, wt2)]
+	store.mu.Unlock()
+	if !present {
+		return nil, false
+	}
+
+	// Indexed records are complete and never rewritten, so they can be read without locking.
+	data := make([]byte, val.len)
+	if _, err := store.file.ReadAt(data, val.off); err != nil {
+		return nil, false
+	}
+	poly := &hashPolyBuilder{vmap: util.NewVectorMap(), order: &keyOrder{}}
+	if err := poly.UnmarshalBinary(data); err != nil {
+		return nil, false
+	}
+	return poly.Freeze(), true
+}
+
+// put appends the product of the given weights in the namespace to the store, unless it is already
+// present. Write errors are recorded and reported by Err.
+func (store *ProductStore) put(namespace string, wt1, wt2 Weight, poly WeightPoly) {
+	key := storeKey(namespace, wt1, wt2)
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, present := store.index[key]; present || store.err != nil {
+		return
+	}
+
+	val, err := marshalPolyBinary(poly, polyRootSystem(poly))
+	if err != nil {
+		store.err = err
+		return
+	}
+	if err := lockFile(store.file); err != nil {
+		store.err = err
+		return
+	}
+	defer func() {
+		if err := unlockFile(store.file); err != nil && store.err == nil {
+			store.err = err
+		}
+	}()
+
+	if err := store.update(); err != nil {
+		store.err = err
+		return
+	}
+	if _, present := store.index[key]; present {
+		return
+	}
+	rec := encodeRecord(key, val)
+	if _, err := store.file.Write(rec); err != nil {
+		store.err = err
+		return
+	}
+	store.index[key] = storedValue{store.end + recordHeaderLen + int64(len(key)), len(val)}
+	store.end += int64(len(rec))
+}
+
+// update indexes the records appended to the file since the last update, and truncates the file
+// after the last complete record. The file must be locked.
+func (store *ProductStore) update() error {
+	info, err := store.file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+
+	rdr := bufio.NewReader(io.NewSectionReader(store.file, store.end, size-store.end))
+	for store.end < size {
+		key, valLen, err := readRecord(rdr, size-store.end)
+		if err == errIncompleteRecord {
+			return store.file.Truncate(store.end)
+		}
+		if err != nil {
+			return fmt.Errorf("lie: invalid product store record at offset %v: %v", store.end, err)
+		}
+
+		valOff := store.end + recordHeaderLen + int64(len(key))
+		if _, present := store.index[key]; !present {
+			store.index[key] = storedValue{valOff, valLen}
+		}
+		store.end = valOff + int64(valLen)
+	}
+	return nil
+}
+
+// encodeRecord encodes the key and value as a record.
+func encodeRecord(key string, val []byte) []byte {
+	rec := make([]byte, recordHeaderLen, recordHeaderLen+len(key)+len(val))
+	binary.BigEndian.PutUint32(rec[0:], uint32(len(key)))
+	binary.BigEndian.PutUint32(rec[4:], uint32(len(val)))
+	binary.BigEndian.PutUint32(rec[8:], crc32.ChecksumIEEE(rec[:8]))
+	rec = append(rec, key...)
+	rec = append(rec, val...)
+	binary.BigEndian.PutUint32(rec[12:], crc32.ChecksumIEEE(rec[recordHeaderLen:]))
+	return rec
+}
+
+// readRecord reads a record from a reader with the given number of bytes remaining, and returns
+// its key and the length of its value. It returns errIncompleteRecord if the record extends past
+// the remaining bytes.
+func readRecord(rdr io.Reader, remaining int64) (string, int, error) {
+	if remaining < recordHeaderLen {
+		return "", 0, errIncompleteRecord
+	}
+	var header [recordHeaderLen]byte
+	if _, err := io.ReadFull(rdr, header[:]); err != nil {
+		return "", 0, err
+	}
+	if crc32.ChecksumIEEE(header[:8]) != binary.BigEndian.Uint32(header[8:]) {
+		return "", 0, errors.New("header checksum mismatch")
+	}
+
+	keyLen := int64(binary.BigEndian.Uint32(header[0:]))
+	valLen := int64(binary.BigEndian.Uint32(header[4:]))
+	if recordHeaderLen+keyLen+valLen > remaining {
+		return "", 0, errIncompleteRecord
+	}
+	data := make([]byte, keyLen+valLen)
+	if _, err := io.ReadFull(rdr, data); err != nil {
+		return "", 0, err
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[12:]) {
+		return "", 0, errors.New("checksum mismatch")
+	}
+	return string(data[:keyLen]), int(valLen), nil
+}
+
+// storeKey encodes the namespace and pair of weights as a record key.
+func storeKey(namespace string, wt1, wt2 Weight) string {
+	key := appendUvarint(nil, uint64(len(namespace)))
+	key = append(key, namespace...)
+	key = appendUvarint(key, uint64(len(wt1)))
+	for _, coord := range pairKey(wt1, wt2) {
+		key = appendVarint(key, int64(coord))
+	}
+	return string(key)
+}
@@ -0,0 +1,17 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package lie
+
+import "os"
+
+// lockFile does nothing on platforms without advisory file locks; the file of a ProductStore must
+// then be used by a single process at a time.
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// unlockFile does nothing on platforms without advisory file locks.
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,250 @@
+package lie
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProductStoreReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lieprod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "products.log")
+
+	store, err := OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) returned error %v", path, err)
+	}
+	alg := NewAlgebra(NewTypeARootSystem(2), WithProductStore(store))
+	wts := []Weight{{1, 1}, {2, 0}, {1, 1}, {0, 1}}
+	want := alg.Fusion(3, wts...)
+	numEntries := store.Len()
+	if numEntries == 0 || store.Err() != nil {
+		t.Errorf("Store contains %v products with error %v after Fusion", numEntries, store.Err())
+	}
+	if err := store.Close(); err != nil {
+		t.Errorf("Close() returned error %v", err)
+	}
+
+	store, err = OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) returned error %v", path, err)
+	}
+	defer store.Close()
+	if store.Len() != numEntries {
+		t.Errorf("Reloaded store contains %v products, want %v", store.Len(), numEntries)
+	}
+
+	// Reloaded products are not recomputed.
+	var calls int32
+	alg = NewAlgebra(NewTypeARootSystem(2))
+	prod := NewMemoizedProduct(func(wt1, wt2 Weight) MutableWeightPoly {
+		atomic.AddInt32(&calls, 1)
+		return alg.(algebraImpl).fusionProduct(3, wt1, wt2)
+	}, WithStore(store, "A2/fusion/3"))
+	polys := make([]WeightPoly, len(wts))
+	for i := range wts {
+		polys[i] = wts[i]
+	}
+	if got := prod.Reduce(polys...); FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+		t.Errorf("Reduce(%v) from store = %v, want %v", wts, got, want)
+	}
+	if calls != 0 {
+		t.Errorf("Product with reloaded store computed %v products, want 0", calls)
+	}
+
+	// Other namespaces do not share products.
+	prod = NewMemoizedProduct(func(wt1, wt2 Weight) MutableWeightPoly {
+		atomic.AddInt32(&calls, 1)
+		return alg.(algebraImpl).fusionProduct(2, wt1, wt2)
+	}, WithStore(store, "A2/fusion/2"))
+	if got, want := prod.Apply(Weight{1, 1}, Weight{2, 0}), alg.Fusion(2, Weight{1, 1}, Weight{2, 0}); FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+		t.Errorf("Apply([1,1], [2,0]) at level 2 = %v, want %v", got, want)
+	}
+	if calls != 1 || store.Len() != numEntries+1 {
+		t.Errorf("Product in new namespace computed %v products and stored %v, want 1 and %v",
+			calls, store.Len(), numEntries+1)
+	}
+}
+
+func TestProductStoreTruncatedRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lieprod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "products.log")
+
+	store, err := OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) returned error %v", path, err)
+	}
+	alg := NewAlgebra(NewTypeARootSystem(1), WithProductStore(store))
+	alg.Fusion(4, Weight{1}, Weight{2})
+	alg.Fusion(4, Weight{2}, Weight{3})
+	store.Close()
+
+	// Simulate a write interrupted in the middle of the last record.
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, info.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) of truncated log returned error %v", path, err)
+	}
+	if store.Len() != 1 {
+		t.Errorf("Truncated store contains %v products, want 1", store.Len())
+	}
+	alg = NewAlgebra(NewTypeARootSystem(1), WithProductStore(store))
+	if got := alg.Fusion(4, Weight{2}, Weight{3}); FormatPoly(got, DynkinFmt) != "[3] + [1]" {
+		t.Errorf("Fusion(4, [2], [3]) = %v, want [3] + [1]", got)
+	}
+	store.Close()
+
+	store, err = OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) returned error %v", path, err)
+	}
+	defer store.Close()
+	if store.Len() != 2 {
+		t.Errorf("Repaired store contains %v products, want 2", store.Len())
+	}
+}
+
+func TestProductStoreSharedFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lieprod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "products.log")
+
+	store1, err := OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) returned error %v", path, err)
+	}
+	store2, err := OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) returned error %v", path, err)
+	}
+	alg1 := NewAlgebra(NewTypeARootSystem(1), WithProductStore(store1))
+	alg2 := NewAlgebra(NewTypeARootSystem(1), WithProductStore(store2))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			alg1.Fusion(5, Weight{i}, Weight{i + 1})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			alg2.Fusion(5, Weight{i + 1}, Weight{i + 2})
+		}(i)
+	}
+	wg.Wait()
+	if store1.Err() != nil || store2.Err() != nil {
+		t.Errorf("Stores returned errors %v and %v", store1.Err(), store2.Err())
+	}
+	store1.Close()
+	store2.Close()
+
+	// Both stores' records are kept, and products stored by both are not duplicated.
+	store, err := OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) of shared log returned error %v", path, err)
+	}
+	defer store.Close()
+	if store.Len() != 5 {
+		t.Errorf("Shared store contains %v products, want 5", store.Len())
+	}
+	alg := NewAlgebra(NewTypeARootSystem(1), WithProductStore(store))
+	if got := alg.Fusion(5, Weight{4}, Weight{3}); FormatPoly(got, DynkinFmt) != "[3] + [1]" {
+		t.Errorf("Fusion(5, [4], [3]) = %v, want [3] + [1]", got)
+	}
+}
+
+func TestProductStoreCorruptRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lieprod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "products.log")
+
+	store, err := OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) returned error %v", path, err)
+	}
+	alg := NewAlgebra(NewTypeARootSystem(1), WithProductStore(store))
+	alg.Fusion(4, Weight{1}, Weight{2})
+	alg.Fusion(4, Weight{2}, Weight{3})
+	store.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, off := range []int{0, recordHeaderLen + 1} {
+		corrupt := append([]byte(nil), data...)
+		corrupt[off] ^= 0xff
+		if err := ioutil.WriteFile(path, corrupt, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if store, err := OpenProductStore(path); err == nil {
+			store.Close()
+			t.Errorf("OpenProductStore accepted a log corrupted at offset %v", off)
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() != int64(len(data)) {
+			t.Errorf("OpenProductStore truncated a log corrupted at offset %v", off)
+		}
+	}
+}
+
+func TestProductStoreTensor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lieprod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "products.log")
+
+	store, err := OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) returned error %v", path, err)
+	}
+	alg := NewAlgebra(NewTypeARootSystem(2), WithProductStore(store))
+	want := alg.Tensor(Weight{1, 1}, Weight{2, 0})
+	if store.Len() != 1 || store.Err() != nil {
+		t.Errorf("Store contains %v products with error %v after Tensor, want 1", store.Len(), store.Err())
+	}
+	store.Close()
+
+	store, err = OpenProductStore(path)
+	if err != nil {
+		t.Fatalf("OpenProductStore(%v) returned error %v", path, err)
+	}
+	defer store.Close()
+	var calls int32
+	alg = NewAlgebra(NewTypeARootSystem(2))
+	prod := NewMemoizedProduct(func(wt1, wt2 Weight) MutableWeightPoly {
+		atomic.AddInt32(&calls, 1)
+		return alg.(algebraImpl).tensorProduct(wt1, wt2)
+	}, WithStore(store, "A2/tensor"))
+	if got := prod.Apply(Weight{2, 0}, Weight{1, 1}); FormatPoly(got, DynkinFmt) != FormatPoly(want, DynkinFmt) {
+		t.Errorf("Apply([2,0], [1,1]) from store = %v, want %v", got, want)
+	}
+	if calls != 0 {
+		t.Errorf("Tensor product with reloaded store computed %v products, want 0", calls)
+	}
+}
@@ -63,6 +63,16 @@ func (wt Weight) Multiplicity(other Weight) *big.Int {
 	return big.NewInt(0)
 }
 
+// compareLex compares weights lexicographically, with larger weights first.
+func compareLex(wt1, wt2 Weight) int {
+	for i := range wt1 {
+		if wt1[i] != wt2[i] {
+			return wt2[i] - wt1[i]
+		}
+	}
+	return 0
+}
+
 // Root represents a root in the root lattice.
 type Root []int
 